@@ -0,0 +1,32 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/castrojo/firehose-go/internal/atomicfile"
+	"github.com/castrojo/firehose-go/internal/codegen"
+)
+
+// runCodegen implements "firehose codegen ts": write TypeScript declarations
+// for releases.json generated from the models.
+func runCodegen(args []string) {
+	if len(args) == 0 || args[0] != "ts" {
+		log.Fatalf("Usage: firehose codegen ts [-o path]")
+	}
+
+	fs := flag.NewFlagSet("codegen ts", flag.ExitOnError)
+	out := fs.String("o", codegen.TypeScriptPath, `output .d.ts file ("-" for stdout)`)
+	fs.Parse(args[1:])
+
+	data := codegen.TypeScript()
+	if *out == "-" {
+		os.Stdout.Write(data)
+		return
+	}
+	if err := atomicfile.WriteFile(*out, data, 0644, nil); err != nil {
+		log.Fatalf("Failed to write TypeScript declarations: %v", err)
+	}
+	log.Printf("✅ Wrote %s", *out)
+}
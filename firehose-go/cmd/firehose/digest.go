@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"log"
+	"os"
+	"time"
+
+	"github.com/castrojo/firehose-go/internal/atomicfile"
+	"github.com/castrojo/firehose-go/internal/digest"
+	"github.com/castrojo/firehose-go/internal/health"
+	"github.com/castrojo/firehose-go/internal/landscape"
+	"github.com/castrojo/firehose-go/internal/models"
+)
+
+// runDigest implements "firehose digest".
+func runDigest(args []string) {
+	fs := flag.NewFlagSet("digest", flag.ExitOnError)
+	since := fs.String("since", "7d", "start of the digest window: days or weeks (7d, 2w), a duration (36h) or YYYY-MM-DD")
+	format := fs.String("format", "markdown", "markdown or html")
+	in := fs.String("in", outputPath, "releases.json to summarize")
+	statePath := fs.String("state", healthStatePath, "feed health state, used to tell newly broken feeds from long-failing ones")
+	out := fs.String("o", "", "write the digest to this file instead of stdout")
+	fs.Parse(args)
+	if fs.NArg() != 0 || (*format != "markdown" && *format != "html") {
+		log.Fatalf("Usage: firehose digest [-since 7d] [-format markdown|html] [-in path] [-state path] [-o path]")
+	}
+
+	output, err := models.ReadJSON(*in)
+	if err != nil {
+		log.Fatalf("Failed to read output: %v", err)
+	}
+	// The window ends when the output was generated, so a digest of an older
+	// file covers the week before it rather than the week before today.
+	until, err := time.Parse(time.RFC3339, output.Metadata.GeneratedAt)
+	if err != nil {
+		until = time.Now().UTC()
+	}
+	start, err := digest.ParseSince(*since, until)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	landscapeData, err := landscape.FetchAndParse()
+	if err != nil {
+		log.Printf("⚠️  Landscape unavailable, digest will not list maturity changes: %v", err)
+	}
+	// A missing state file loads as empty: every failing feed is then listed as broken.
+	state, err := health.Load(*statePath)
+	if err != nil {
+		log.Printf("⚠️  Ignoring feed health state: %v", err)
+	}
+
+	d := digest.Build(output, landscapeData, state, start, until)
+	var buf bytes.Buffer
+	if *format == "html" {
+		err = d.HTML(&buf)
+	} else {
+		err = d.Markdown(&buf)
+	}
+	if err != nil {
+		log.Fatalf("Failed to render digest: %v", err)
+	}
+	if *out == "" {
+		os.Stdout.Write(buf.Bytes())
+		return
+	}
+	if err := atomicfile.WriteFile(*out, buf.Bytes(), 0644, nil); err != nil {
+		log.Fatalf("Failed to write digest: %v", err)
+	}
+	log.Printf("✅ Digest for %s: %s", d.Period(), *out)
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/castrojo/firehose-go/internal/atomicfile"
+	"github.com/castrojo/firehose-go/internal/export"
+)
+
+// runExport implements "firehose export opml" and
+// "firehose export -format ndjson|csv".
+func runExport(args []string) {
+	if len(args) > 0 && args[0] == "opml" {
+		runExportOPML(args[1:])
+		return
+	}
+	usage := "Usage: firehose export opml [-o path] | firehose export -format ndjson|csv [-columns a,b] [-since date] [-until date] [-kind release|news] [-in path | -db path] [-o path] [-escape-formulas=false]"
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	format := fs.String("format", "", "record format: "+strings.Join(export.Formats, " or "))
+	columns := fs.String("columns", "", "comma-separated columns to export (default: all)")
+	since := fs.String("since", "", "only items published on or after this date (YYYY-MM-DD or RFC 3339)")
+	until := fs.String("until", "", "only items published before this time, or on or before this date")
+	kind := fs.String("kind", "", "only "+export.KindRelease+" or "+export.KindNews+" items")
+	in := fs.String("in", outputPath, "releases.json to read")
+	db := fs.String("db", "", "read the SQLite history store at this path instead of releases.json")
+	out := fs.String("o", "", "write to this file instead of stdout")
+	escapeFormulas := fs.Bool("escape-formulas", true, "prefix CSV cells starting with = + - @, tab or CR with ' so spreadsheets do not run them as formulas")
+	fs.Usage = func() {
+		log.Print(usage)
+		fs.PrintDefaults()
+		log.Printf("Columns: %s", columnNames())
+	}
+	fs.Parse(args)
+	if *format == "" || fs.NArg() != 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	var names []string
+	if *columns != "" {
+		names = strings.Split(*columns, ",")
+	}
+	cols, err := export.SelectColumns(names)
+	if err != nil {
+		log.Fatalf("Invalid -columns: %v (available: %s)", err, columnNames())
+	}
+	var filter export.Filter
+	if filter.Since, err = export.ParseBound(*since, false); err != nil {
+		log.Fatalf("Invalid -since: %v", err)
+	}
+	if filter.Until, err = export.ParseBound(*until, true); err != nil {
+		log.Fatalf("Invalid -until: %v", err)
+	}
+	switch *kind {
+	case "", export.KindRelease, export.KindNews:
+		filter.Kind = *kind
+	default:
+		log.Fatalf("Invalid -kind %q: want %s or %s", *kind, export.KindRelease, export.KindNews)
+	}
+
+	n := 0
+	write := func(w io.Writer) error {
+		enc, err := export.NewWriter(*format, w, cols, export.Options{KeepFormulas: !*escapeFormulas})
+		if err != nil {
+			return err
+		}
+		emit := func(r export.Record) error {
+			n++
+			return enc.Write(r)
+		}
+		if *db != "" {
+			err = export.ReadSQLite(*db, filter, emit)
+		} else {
+			err = readOutput(*in, filter, emit)
+		}
+		if err != nil {
+			return err
+		}
+		return enc.Flush()
+	}
+
+	if *out == "" {
+		err = write(os.Stdout)
+	} else {
+		err = atomicfile.Write(*out, 0644, write, nil)
+	}
+	if err != nil {
+		log.Fatalf("Export failed: %v", err)
+	}
+	if *out != "" {
+		log.Printf("✅ Exported %d records to %s", n, *out)
+	}
+}
+
+// readOutput streams records from a releases.json file.
+func readOutput(path string, filter export.Filter, fn func(export.Record) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return export.ReadJSON(file, filter, fn)
+}
+
+func columnNames() string {
+	names := make([]string, len(export.Columns))
+	for i, c := range export.Columns {
+		names[i] = c.Name
+	}
+	return strings.Join(names, ", ")
+}
@@ -4,16 +4,68 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 
+	"github.com/castrojo/firehose-go/internal/atomicfile"
+	"github.com/castrojo/firehose-go/internal/calendar"
 	"github.com/castrojo/firehose-go/internal/feeds"
+	"github.com/castrojo/firehose-go/internal/health"
 	"github.com/castrojo/firehose-go/internal/landscape"
+	"github.com/castrojo/firehose-go/internal/metrics"
 	"github.com/castrojo/firehose-go/internal/models"
+	"github.com/castrojo/firehose-go/internal/policy"
+	"github.com/castrojo/firehose-go/internal/robots"
+	"github.com/castrojo/firehose-go/internal/schema"
+	"github.com/castrojo/firehose-go/internal/sqlite"
+	landscapesync "github.com/castrojo/firehose-go/internal/sync"
+	"github.com/castrojo/firehose-go/internal/syndicate"
+	"github.com/castrojo/firehose-go/internal/validate"
 )
 
 const version = "1.0.0"
 
+// configPath is the feed configuration read by the pipeline and its subcommands.
+const configPath = "config/feeds.yaml"
+
+// outputPath is the pipeline's JSON output, read back by export.
+const outputPath = "../src/data/releases.json"
+
+// healthStatePath is the persisted per-feed health record (gitignored, cached in CI).
+const healthStatePath = "state/feed-health.json"
+
+// invalidRecordsPath receives records that failed validation when
+// validation.invalid is "quarantine".
+const invalidRecordsPath = "state/invalid-records.json"
+
+// publicDir is the Astro site's static directory; files written here are
+// published as-is at the site root.
+const publicDir = "../public"
+
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "codegen":
+			runCodegen(os.Args[2:])
+		case "digest":
+			runDigest(os.Args[2:])
+		case "export":
+			runExport(os.Args[2:])
+		case "import":
+			runImport(os.Args[2:])
+		case "report":
+			runReport(os.Args[2:])
+		case "schema":
+			runSchema(os.Args[2:])
+		default:
+			log.Fatalf("Unknown command %q (available: codegen, digest, export, import, report, schema)", os.Args[1])
+		}
+		return
+	}
+
 	startTime := time.Now()
 
 	log.Printf("Firehose Go Pipeline v%s", version)
@@ -21,36 +73,127 @@ func main() {
 
 	// Step 1: Fetch and parse CNCF Landscape
 	log.Println("Fetching CNCF Landscape data...")
-	landscapeStart := time.Now()
+	stop := metrics.Default.Start(metrics.StageLandscape)
 	landscapeData, err := landscape.FetchAndParse()
 	if err != nil {
 		log.Fatalf("Failed to fetch landscape: %v", err)
 	}
-	landscapeDuration := time.Since(landscapeStart)
-	log.Printf("Parsed %d landscape projects in %s", len(landscapeData), landscapeDuration)
+	stop()
+	log.Printf("Parsed %d landscape projects in %s", len(landscapeData), metrics.Default.Stage(metrics.StageLandscape))
 
 	// Step 2: Load feed configuration
 	log.Println("Loading feed configuration...")
-	feedConfig, err := feeds.LoadConfig("config/feeds.yaml")
+	feedConfig, err := feeds.LoadConfig(configPath)
 	if err != nil {
 		log.Fatalf("Failed to load feed config: %v", err)
 	}
 	log.Printf("Loaded %d feeds", len(feedConfig.Feeds))
 	log.Printf("Loaded %d blog feeds", len(feedConfig.Blogs))
+	robots.SetUserAgent(feedConfig.UserAgent)
+	log.Printf("Fetching as %q (robots.txt honoured)", robots.Default.UserAgent())
+	if errs := validate.Struct(feedConfig.Validation); len(errs) > 0 {
+		log.Fatalf("Invalid validation config: %v", errs[0])
+	}
+	if errs := validate.Struct(feedConfig.Output); len(errs) > 0 {
+		log.Fatalf("Invalid output config: %v", errs[0])
+	}
+
+	// Step 2a: Skip feed sources that fail validation (bad URL or category)
+	stop = metrics.Default.Start(metrics.StageValidation)
+	validFeeds, invalid := validate.Filter("source", feedConfig.Feeds)
+	stop()
+
+	// Step 2b: Skip quarantined feeds until their re-probe is due; must-succeed
+	// feeds are fetched every run
+	healthState, err := health.Load(healthStatePath)
+	if err != nil {
+		log.Printf("Warning: %v; starting with empty feed health", err)
+		healthState = health.NewState()
+	}
+	mustSucceed := make(map[string]bool, len(feedConfig.Policy.MustSucceed))
+	for _, feedURL := range feedConfig.Policy.MustSucceed {
+		mustSucceed[feedURL] = true
+	}
+	activeFeeds, skippedFeeds := health.Active(healthState, validFeeds, func(f models.FeedSource) string { return f.URL }, mustSucceed, startTime)
+	activeBlogs, skippedBlogs := health.Active(healthState, feedConfig.Blogs, func(b models.BlogSource) string { return b.URL }, mustSucceed, startTime)
+	if skippedFeeds+skippedBlogs > 0 {
+		log.Printf("⏸️  Skipping %d quarantined feeds and %d quarantined blog feeds", skippedFeeds, skippedBlogs)
+	}
 
 	// Step 3: Fetch all feeds in parallel
 	log.Println("Fetching feeds in parallel...")
-	feedsStart := time.Now()
-	results := feeds.FetchAllFeeds(feedConfig.Feeds, landscapeData)
-	feedsDuration := time.Since(feedsStart)
-	log.Printf("Fetched %d feeds in %s", len(results.Feeds), feedsDuration)
+	stop = metrics.Default.Start(metrics.StageFeeds)
+	results := feeds.FetchAllFeeds(activeFeeds, landscapeData)
+	stop()
+	log.Printf("Fetched %d feeds in %s", len(results.Feeds), metrics.Default.Stage(metrics.StageFeeds))
 
 	// Step 3b: Fetch blog feeds in parallel
 	log.Println("Fetching blog feeds...")
-	blogStart := time.Now()
-	blogResults := feeds.FetchBlogFeeds(feedConfig.Blogs, landscapeData)
+	stop = metrics.Default.Start(metrics.StageBlogs)
+	blogResults := feeds.FetchBlogFeeds(activeBlogs, landscapeData)
+	stop()
 	log.Printf("Fetched %d blog feeds in %s — %d news items",
-		len(blogResults.Feeds), time.Since(blogStart), len(blogResults.Releases))
+		len(blogResults.Feeds), metrics.Default.Stage(metrics.StageBlogs), len(blogResults.Releases))
+
+	// Step 3c: Detect anomalies against each feed's history, then record feed
+	// health and quarantine feeds that keep failing
+	rules := health.AnomalyRules{
+		BurstItems:  feedConfig.Anomalies.BurstItems,
+		BurstWindow: feedConfig.Anomalies.BurstWindow,
+	}
+	anomalies := []health.Anomaly{}
+	anomalies = append(anomalies, healthState.Detect(results.Feeds, results.Releases, rules)...)
+	anomalies = append(anomalies, healthState.Detect(blogResults.Feeds, blogResults.Releases, rules)...)
+	updateHealth(healthState, feedConfig, results, blogResults)
+	heldItems := 0
+	heldAt := time.Now().UTC()
+	if feedConfig.Anomalies.HoldBursts {
+		reviewed := make(map[string]time.Time, len(feedConfig.Anomalies.AllowBursts))
+		for _, r := range feedConfig.Anomalies.AllowBursts {
+			reviewed[r.Feed] = r.Reviewed
+		}
+		var heldReleases, heldNews int
+		results.Releases, heldReleases = healthState.Hold(anomalies, results.Releases, reviewed, heldAt)
+		blogResults.Releases, heldNews = healthState.Hold(anomalies, blogResults.Releases, reviewed, heldAt)
+		heldItems = heldReleases + heldNews
+		saveHealth(healthState)
+	}
+	for _, a := range anomalies {
+		log.Printf("🚨 Anomaly %s in %s: %s", a.Rule, a.FeedURL, a.Detail)
+	}
+	if heldItems > 0 {
+		log.Printf("⏸️  Holding %d burst items for review (add {feed, reviewed: %s} to anomalies.allow_bursts to release)",
+			heldItems, heldAt.Format(time.RFC3339))
+	}
+
+	// Step 3d: Merge maturity-change announcements recorded by cmd/sync
+	announcements, err := landscapesync.LoadAnnouncements(landscapesync.AnnouncementsPath)
+	if err != nil {
+		log.Printf("Warning: failed to load announcements: %v", err)
+	}
+	if len(announcements) > 0 {
+		blogResults.Releases = append(blogResults.Releases, announcements...)
+		sort.Slice(blogResults.Releases, func(i, j int) bool {
+			return blogResults.Releases[i].PubDate.After(blogResults.Releases[j].PubDate)
+		})
+		log.Printf("Merged %d maturity announcements into news", len(announcements))
+	}
+
+	// Step 3e: Validate every record against its validate tags; drop (or quarantine) invalid ones.
+	// Invalid feed statuses still count towards the feed stats and the policy;
+	// they are only left out of the output.
+	stop = metrics.Default.Start(metrics.StageValidation)
+	var rejected []validate.Invalid
+	results.Releases, rejected = validate.Filter("release", results.Releases)
+	invalid = append(invalid, rejected...)
+	blogResults.Releases, rejected = validate.Filter("news", blogResults.Releases)
+	invalid = append(invalid, rejected...)
+	validStatuses, rejected := validate.Filter("feed", results.Feeds)
+	invalid = append(invalid, rejected...)
+	validBlogStatuses, rejected := validate.Filter("feed", blogResults.Feeds)
+	invalid = append(invalid, rejected...)
+	stop()
+	handleInvalid(invalid, feedConfig.Validation.Invalid)
 
 	// Step 4: Collect statistics
 	successCount := 0
@@ -66,69 +209,130 @@ func main() {
 	log.Printf("Feed results: %d successful, %d failed", successCount, failCount)
 	log.Printf("Total releases: %d", len(results.Releases))
 
-	// Check if we have enough successful feeds (>50% threshold)
-	successRate := float64(successCount) / float64(len(feedConfig.Feeds))
-	if successRate < 0.5 {
-		log.Fatalf("Catastrophic failure: only %.1f%% feeds succeeded (threshold: 50%%)", successRate*100)
+	// Step 4a: Enforce the success policy (per kind, per category, must-succeed feeds);
+	// quarantined feeds are not fetched and not counted
+	breaches := policy.Evaluate(feedConfig, results.Feeds, blogResults.Feeds)
+	for _, b := range breaches {
+		log.Printf("❌ Policy %s breached: %s", b.Policy, b.Detail)
+	}
+
+	// Summary as JSON for GitHub Actions
+	summary := map[string]interface{}{
+		"success":         len(breaches) == 0,
+		"duration":        time.Since(startTime).String(),
+		"feeds_total":     len(validFeeds),
+		"feeds_ok":        successCount,
+		"feeds_failed":    failCount,
+		"feeds_skipped":   skippedFeeds,
+		"releases":        len(results.Releases),
+		"news":            len(blogResults.Releases),
+		"blog_feeds":      len(feedConfig.Blogs),
+		"anomalies":       anomalies,
+		"held_items":      heldItems,
+		"invalid_records": len(invalid),
+		"policy_breaches": breaches,
+	}
+	if len(breaches) > 0 {
+		printSummary(summary)
+		log.Printf("Not writing output: %d policy breaches", len(breaches))
+		os.Exit(policy.ExitCode(breaches))
+	}
+
+	allFeeds := append(results.Feeds, blogResults.Feeds...)
+	feedsWithWarnings, warningCounts := feeds.SummarizeWarnings(allFeeds)
+	if feedsWithWarnings > 0 {
+		log.Printf("⚠️  %d feeds have lint warnings: %v", feedsWithWarnings, warningCounts)
 	}
 
 	// Step 5: Build output structure
 	buildDuration := time.Since(startTime)
 	output := &models.OutputData{
 		Metadata: models.Metadata{
-			SchemaVersion: "1.0.0",
+			SchemaVersion: models.SchemaVersion,
 			GeneratedAt:   time.Now().UTC().Format(time.RFC3339),
 			GeneratedBy:   fmt.Sprintf("firehose-go v%s", version),
 			BuildDuration: buildDuration.String(),
 			Stats: models.Stats{
-				FeedsTotal:               len(feedConfig.Feeds),
+				FeedsTotal:               len(validFeeds),
 				FeedsSuccessful:          successCount,
 				FeedsFailed:              failCount,
-				FeedsSkipped:             0,
+				FeedsSkipped:             skippedFeeds,
+				FeedsWithWarnings:        feedsWithWarnings,
+				InvalidRecords:           len(invalid),
 				ReleasesTotal:            len(results.Releases),
 				NewsTotal:                len(blogResults.Releases),
 				BlogFeedsTotal:           len(feedConfig.Blogs),
 				LandscapeProjectsTotal:   len(landscapeData),
 				LandscapeProjectsMatched: countMatchedProjects(results.Releases),
+				Warnings:                 warningCounts,
 			},
-			Performance: models.Performance{
-				LandscapeFetchDuration: landscapeDuration.String(),
-				FeedsFetchDuration:     feedsDuration.String(),
-				EnrichmentDuration:     "0s", // Tracked during fetch
-				ValidationDuration:     "0s", // Tracked during fetch
-				OutputDuration:         "0s", // Will be updated
-			},
+			Performance: metrics.Default.Performance(),
 		},
 		Releases: results.Releases,
 		News:     blogResults.Releases,
-		Feeds:    append(results.Feeds, blogResults.Feeds...),
+		Feeds:    append(validStatuses, validBlogStatuses...),
 	}
 
 	// Step 6: Write output JSON
 	log.Println("Writing output JSON...")
-	outputStart := time.Now()
-	outputPath := "../src/data/releases.json"
+	stop = metrics.Default.Start(metrics.StageOutput)
 	if err := output.WriteJSON(outputPath); err != nil {
 		log.Fatalf("Failed to write output: %v", err)
 	}
-	outputDuration := time.Since(outputStart)
-	output.Metadata.Performance.OutputDuration = outputDuration.String()
+	if err := schema.Write(models.SchemaPath(outputPath)); err != nil {
+		log.Fatalf("Failed to write output schema: %v", err)
+	}
+	if feedConfig.Output.Shards {
+		shardDir := models.ShardDir(publicDir)
+		index, err := output.WriteShards(shardDir)
+		if err != nil {
+			log.Fatalf("Failed to write sharded output: %v", err)
+		}
+		log.Printf("🗂️  Sharded output: %s (%d project shards, %d month shards)", shardDir, len(index.Projects), len(index.Months))
+	}
+	if feedConfig.Output.Feeds {
+		feedDir := filepath.Join(publicDir, syndicate.PublicDir)
+		n, err := syndicate.Write(feedDir, feedConfig.Output.SiteURL, syndicate.Build(output, feedConfig.Output.FeedItems))
+		if err != nil {
+			log.Fatalf("Failed to write syndication feeds: %v", err)
+		}
+		log.Printf("📡 Feeds: %d Atom, RSS and JSON Feed files in %s", n, feedDir)
+	}
+	if feedConfig.Output.Calendars {
+		calDir := filepath.Join(publicDir, calendar.PublicDir)
+		n, err := calendar.Write(calDir, calendar.Build(output, landscapeData))
+		if err != nil {
+			log.Fatalf("Failed to write calendars: %v", err)
+		}
+		log.Printf("📅 Calendars: %d iCalendar files in %s", n, calDir)
+	}
+	if dbPath := feedConfig.Output.SQLite; dbPath != "" {
+		if err := sqlite.Write(dbPath, output, feedConfig, landscapeData); err != nil {
+			log.Fatalf("Failed to write SQLite database: %v", err)
+		}
+		log.Printf("🗄️  SQLite: %s", dbPath)
+	}
+	stop()
+
+	// The output cannot contain its own write time; the sidecar has the final timings.
+	output.Metadata.Performance = metrics.Default.Performance()
+	if err := output.WritePerformance(models.PerformancePath(outputPath)); err != nil {
+		log.Printf("Warning: failed to write performance sidecar: %v", err)
+	}
+	perf := output.Metadata.Performance
+	log.Printf("⏱️  %d requests, %.1f MB, %d retries; output written in %s",
+		perf.Requests, float64(perf.BytesTransferred)/1e6, perf.Retries, perf.OutputDuration)
 
 	// Log final summary
 	log.Printf("✅ Pipeline complete in %s", buildDuration)
 	log.Printf("📊 Output: %s", outputPath)
 
-	// Write summary as JSON for GitHub Actions
-	summary := map[string]interface{}{
-		"success":      true,
-		"duration":     buildDuration.String(),
-		"feeds_total":  len(feedConfig.Feeds),
-		"feeds_ok":     successCount,
-		"feeds_failed": failCount,
-		"releases":     len(results.Releases),
-		"news":         len(blogResults.Releases),
-		"blog_feeds":   len(feedConfig.Blogs),
-	}
+	summary["duration"] = buildDuration.String()
+	printSummary(summary)
+}
+
+// printSummary writes the run summary JSON to stdout.
+func printSummary(summary map[string]interface{}) {
 	summaryJSON, err := json.MarshalIndent(summary, "", "  ")
 	if err != nil {
 		log.Printf("Warning: failed to marshal summary: %v", err)
@@ -137,6 +341,58 @@ func main() {
 	fmt.Println(string(summaryJSON))
 }
 
+// handleInvalid logs records that failed validation and, in quarantine mode,
+// saves them for inspection. They have already been dropped from the output.
+func handleInvalid(invalid []validate.Invalid, mode string) {
+	for _, inv := range invalid {
+		log.Printf("🚫 Invalid %s dropped: %s", inv.Kind, strings.Join(inv.Errors, "; "))
+	}
+	if mode != "quarantine" {
+		return
+	}
+	if invalid == nil {
+		invalid = []validate.Invalid{}
+	}
+	data, err := json.MarshalIndent(invalid, "", "  ")
+	if err == nil {
+		err = atomicfile.WriteFile(invalidRecordsPath, append(data, '\n'), 0644, nil)
+	}
+	if err != nil {
+		log.Printf("Warning: failed to quarantine invalid records: %v", err)
+		return
+	}
+	if len(invalid) > 0 {
+		log.Printf("Quarantined %d invalid records in %s", len(invalid), invalidRecordsPath)
+	}
+}
+
+// updateHealth folds this run's results into the feed health state and saves it.
+func updateHealth(state *health.State, config *models.FeedConfig, results, blogResults *models.FetchResults) {
+	now := time.Now()
+	quarantined := state.Update(health.KindRelease, results.Feeds, results.Releases, now)
+	quarantined = append(quarantined, state.Update(health.KindBlog, blogResults.Feeds, blogResults.Releases, now)...)
+	for _, feedURL := range quarantined {
+		log.Printf("🚫 Quarantined %s after %d consecutive failures (re-probe in %s)",
+			feedURL, state.Feeds[feedURL].ConsecutiveFailures, health.ReprobeInterval)
+	}
+
+	configured := make(map[string]bool, len(config.Feeds)+len(config.Blogs))
+	for _, f := range config.Feeds {
+		configured[f.URL] = true
+	}
+	for _, b := range config.Blogs {
+		configured[b.URL] = true
+	}
+	state.Prune(configured)
+	saveHealth(state)
+}
+
+func saveHealth(state *health.State) {
+	if err := state.Save(healthStatePath); err != nil {
+		log.Printf("Warning: failed to save feed health: %v", err)
+	}
+}
+
 // countMatchedProjects counts unique projects with Landscape enrichment
 func countMatchedProjects(releases []models.Release) int {
 	matched := make(map[string]bool)
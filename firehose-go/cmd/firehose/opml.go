@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/castrojo/firehose-go/internal/atomicfile"
+	"github.com/castrojo/firehose-go/internal/feeds"
+	"github.com/castrojo/firehose-go/internal/landscape"
+	"github.com/castrojo/firehose-go/internal/opml"
+	landscapesync "github.com/castrojo/firehose-go/internal/sync"
+)
+
+// runExportOPML implements "firehose export opml".
+func runExportOPML(args []string) {
+	fs := flag.NewFlagSet("export opml", flag.ExitOnError)
+	out := fs.String("o", "", "write the OPML to this file instead of stdout")
+	fs.Parse(args)
+
+	config, err := feeds.LoadConfig(configPath)
+	if err != nil {
+		log.Fatalf("Failed to load feed config: %v", err)
+	}
+	// Titles, homepages and categories come from the landscape; without it the
+	// export still lists every feed, titled by repo and filed under "Other".
+	landscapeData, err := landscape.FetchAndParse()
+	if err != nil {
+		log.Printf("⚠️  Landscape unavailable, exporting without project metadata: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := opml.Export(config, landscapeData, time.Now()).Encode(&buf); err != nil {
+		log.Fatalf("Failed to export OPML: %v", err)
+	}
+	if *out == "" {
+		os.Stdout.Write(buf.Bytes())
+		return
+	}
+	if err := atomicfile.WriteFile(*out, buf.Bytes(), 0644, nil); err != nil {
+		log.Fatalf("Failed to write OPML: %v", err)
+	}
+	log.Printf("✅ Exported %d release feeds and %d blogs to %s", len(config.Feeds), len(config.Blogs), *out)
+}
+
+// runImport implements "firehose import opml <file>".
+func runImport(args []string) {
+	usage := "Usage: firehose import opml [-dry-run] [-category graduated|incubating|sandbox] <file.opml>"
+	if len(args) == 0 || args[0] != "opml" {
+		log.Fatal(usage)
+	}
+	fs := flag.NewFlagSet("import opml", flag.ExitOnError)
+	dryRun := fs.Bool("dry-run", false, "print the feeds.yaml diff instead of writing it")
+	category := fs.String("category", "", "maturity for subscriptions not in the landscape or a tier folder (default: skip them)")
+	fs.Parse(args[1:])
+	if fs.NArg() != 1 {
+		log.Fatal(usage)
+	}
+	switch *category {
+	case "", "graduated", "incubating", "sandbox":
+	default:
+		log.Fatalf("Invalid -category %q: %s", *category, usage)
+	}
+
+	file, err := os.Open(fs.Arg(0))
+	if err != nil {
+		log.Fatalf("Failed to open OPML: %v", err)
+	}
+	doc, err := opml.Parse(file)
+	file.Close()
+	if err != nil {
+		log.Fatalf("Failed to read OPML: %v", err)
+	}
+
+	landscapeData, err := landscape.FetchAndParse()
+	if err != nil {
+		log.Printf("⚠️  Landscape unavailable, using OPML folders for maturity: %v", err)
+	}
+	result, err := landscapesync.ImportOPML(configPath, doc, landscapeData, landscapesync.ImportOptions{
+		Category: *category,
+		DryRun:   *dryRun,
+	})
+	if err != nil {
+		log.Fatalf("Import failed: %v", err)
+	}
+
+	for _, s := range result.Skipped {
+		log.Printf("  skipped %s (%s): %s", s.Title, s.URL, s.Reason)
+	}
+	log.Printf("Imported %d release feeds and %d blogs as pinned (skipped %d)", len(result.Feeds), len(result.Blogs), len(result.Skipped))
+	if *dryRun {
+		fmt.Print(result.Diff)
+		return
+	}
+	summary, _ := json.MarshalIndent(result, "", "  ")
+	fmt.Println(string(summary))
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/castrojo/firehose-go/internal/health"
+)
+
+// runReport implements "firehose report <name>".
+func runReport(args []string) {
+	if len(args) == 0 || args[0] != "health" {
+		log.Fatalf("Usage: firehose report health [-failures N] [-stale-months M] [-state path]")
+	}
+
+	fs := flag.NewFlagSet("report health", flag.ExitOnError)
+	failures := fs.Int("failures", 3, "report feeds that failed this many runs in a row")
+	staleMonths := fs.Int("stale-months", 6, "report feeds with no new item in this many months")
+	statePath := fs.String("state", healthStatePath, "feed health state file written by the pipeline")
+	fs.Parse(args[1:])
+
+	state, err := health.Load(*statePath)
+	if err != nil {
+		log.Fatalf("Failed to load feed health: %v", err)
+	}
+	if len(state.Feeds) == 0 {
+		log.Fatalf("No feed health recorded in %s; run the pipeline first", *statePath)
+	}
+
+	now := time.Now().UTC()
+	findings := state.Unhealthy(*failures, now.AddDate(0, -*staleMonths, 0))
+	if len(findings) == 0 {
+		fmt.Printf("All %d feeds healthy (no feed failing %d+ runs or silent for %d+ months)\n",
+			len(state.Feeds), *failures, *staleMonths)
+		return
+	}
+
+	fmt.Printf("%d of %d feeds need attention\n\n", len(findings), len(state.Feeds))
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "FEED\tKIND\tPROBLEM\tFAILURES\tLAST SUCCESS\tLAST ITEM\tAVG ENTRIES\tLAST ERROR")
+	for _, f := range findings {
+		rec := f.Record
+		lastError := ""
+		if n := len(rec.Errors); n > 0 {
+			lastError = rec.Errors[n-1].Message
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t%s\t%.1f\t%s\n",
+			f.FeedURL, rec.Kind, problem(f), rec.ConsecutiveFailures,
+			formatDate(rec.LastSuccess), formatDate(rec.LastItemDate), rec.AvgEntries, lastError)
+	}
+	w.Flush()
+}
+
+// problem describes why a feed is in the report.
+func problem(f health.Finding) string {
+	var p string
+	switch {
+	case f.Failing && f.Stale:
+		p = "failing, stale"
+	case f.Failing:
+		p = "failing"
+	default:
+		p = "stale"
+	}
+	if f.Record.Quarantined() {
+		p += " (quarantined until " + formatDate(f.Record.NextProbe) + ")"
+	}
+	return p
+}
+
+// formatDate renders a date for the report, or "never" for the zero time.
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return "never"
+	}
+	return t.Format("2006-01-02")
+}
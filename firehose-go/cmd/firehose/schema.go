@@ -0,0 +1,56 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/castrojo/firehose-go/internal/schema"
+)
+
+// publishedSchemaPath is the committed schema that releases.json consumers
+// are built against; "firehose schema -check" compares the models with it.
+const publishedSchemaPath = "schema/releases.schema.json"
+
+// runSchema implements "firehose schema": print the JSON Schema for
+// releases.json, write it to a file, or check it against the published one.
+func runSchema(args []string) {
+	fs := flag.NewFlagSet("schema", flag.ExitOnError)
+	out := fs.String("o", "", "write the schema to this file instead of stdout")
+	check := fs.Bool("check", false, "fail if the models change "+publishedSchemaPath+" without the required version bump")
+	published := fs.String("published", publishedSchemaPath, "published schema to check against")
+	fs.Parse(args)
+
+	if *check {
+		old, err := schema.Load(*published)
+		if err != nil {
+			log.Fatalf("Failed to load published schema: %v", err)
+		}
+		changes, err := schema.Check(old, schema.Generate())
+		for _, ch := range changes {
+			fmt.Println(ch)
+		}
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		if len(changes) > 0 {
+			fmt.Printf("Schema changes are versioned correctly; regenerate %s with: firehose schema -o %s\n", *published, *published)
+			os.Exit(1)
+		}
+		fmt.Printf("✅ Schema matches %s (version %s)\n", *published, old.Version)
+		return
+	}
+
+	if *out != "" {
+		if err := schema.Write(*out); err != nil {
+			log.Fatalf("Failed to write schema: %v", err)
+		}
+		return
+	}
+	data, err := schema.Marshal(schema.Generate())
+	if err != nil {
+		log.Fatalf("Failed to generate schema: %v", err)
+	}
+	os.Stdout.Write(data)
+}
@@ -2,32 +2,98 @@ package main
 
 import (
 	"encoding/json"
+	"flag"
 	"fmt"
 	"log"
+	"os"
+	"time"
 
+	"github.com/castrojo/firehose-go/internal/feeds"
 	"github.com/castrojo/firehose-go/internal/landscape"
+	"github.com/castrojo/firehose-go/internal/models"
+	"github.com/castrojo/firehose-go/internal/robots"
 	landscapesync "github.com/castrojo/firehose-go/internal/sync"
 )
 
+// outputPath is the pipeline output written by cmd/firehose.
+const outputPath = "../src/data/releases.json"
+
 func main() {
+	dryRun := flag.Bool("dry-run", false, "compute changes without writing feeds.yaml or announcements; print a unified diff instead")
+	diffPath := flag.String("diff", "", "with -dry-run, write the unified diff to this path instead of stdout")
+	prBodyPath := flag.String("pr-body", "", "write a Markdown pull request body describing the sync to this path")
+	statePath := flag.String("state", "state/blog-discovery.json", "blog discovery state file used to back off failing blogs (empty disables)")
+	flag.Parse()
+
 	log.Println("Firehose Landscape Sync")
 
+	// Blog discovery identifies itself with the configured User-Agent.
+	if config, err := feeds.LoadConfig("config/feeds.yaml"); err == nil {
+		robots.SetUserAgent(config.UserAgent)
+	}
+
 	landscapeData, err := landscape.FetchAndParse()
 	if err != nil {
 		log.Fatalf("Failed to fetch landscape: %v", err)
 	}
 	log.Printf("Fetched %d landscape projects", len(landscapeData))
 
-	result, err := landscapesync.Run("config/feeds.yaml", landscapeData)
+	opts := landscapesync.Options{
+		Statuses:           loadFeedStatuses("config/feeds.yaml"),
+		DiscoveryStatePath: *statePath,
+	}
+
+	var result *landscapesync.SyncResult
+	var diff string
+	if *dryRun {
+		result, diff, err = landscapesync.DryRun("config/feeds.yaml", landscapeData, opts)
+	} else {
+		result, err = landscapesync.Run("config/feeds.yaml", landscapeData, opts)
+	}
 	if err != nil {
 		log.Fatalf("Sync failed: %v", err)
 	}
 
-	log.Printf("Release feeds: +%d -%d (total %d)",
-		len(result.Added), len(result.Removed), result.Total)
-	log.Printf("Blog feeds: +%d -%d (discovery failed: %d, total: %d)",
+	log.Printf("Release feeds: +%d -%d (skipped: %d, total %d)",
+		len(result.Added), len(result.Removed), len(result.Skipped), result.Total)
+	log.Printf("Blog feeds: +%d -%d (discovery failed: %d, backing off: %d, total: %d)",
 		len(result.BlogsAdded), len(result.BlogsRemoved),
-		len(result.BlogsDiscoveryFailed), result.BlogsTotal)
+		len(result.BlogsDiscoveryFailed), len(result.BlogsDiscoveryDeferred), result.BlogsTotal)
+	log.Printf("Maturity changes: %d, category corrections: %d, moved repos: %d", len(result.Promoted), len(result.Recategorized), len(result.Moved))
+
+	if len(result.Promoted) > 0 {
+		news := landscapesync.PromotionNews(result.Promoted, landscapeData, time.Now())
+		for _, item := range news {
+			log.Printf("📣 %s", item.Title)
+		}
+		if !*dryRun {
+			if err := landscapesync.MergeAnnouncements(landscapesync.AnnouncementsPath, news); err != nil {
+				log.Fatalf("Failed to write announcements: %v", err)
+			}
+		}
+	}
+
+	if *prBodyPath != "" {
+		if err := os.WriteFile(*prBodyPath, []byte(result.Markdown()), 0644); err != nil {
+			log.Fatalf("Failed to write PR body: %v", err)
+		}
+		log.Printf("Wrote PR body to %s", *prBodyPath)
+	}
+
+	if *dryRun {
+		if !result.Changed {
+			log.Println("Dry run: no changes")
+		}
+		if *diffPath == "" {
+			// The diff replaces the JSON result on stdout so it can be piped to a pager.
+			fmt.Print(diff)
+			return
+		}
+		if err := os.WriteFile(*diffPath, []byte(diff), 0644); err != nil {
+			log.Fatalf("Failed to write diff: %v", err)
+		}
+		log.Printf("Dry run: wrote diff to %s", *diffPath)
+	}
 
 	out, err := json.MarshalIndent(result, "", "  ")
 	if err != nil {
@@ -35,3 +101,22 @@ func main() {
 	}
 	fmt.Println(string(out))
 }
+
+// loadFeedStatuses returns the feed results used to detect moved repos.
+// It prefers the statuses recorded in the last pipeline output and falls back
+// to probing the release feeds for redirects when no output is available
+// (e.g. in CI), which needs one HEAD request per feed rather than a full fetch.
+func loadFeedStatuses(configPath string) []models.FeedStatus {
+	if output, err := models.ReadJSON(outputPath); err == nil {
+		log.Printf("Using feed statuses from %s (%s)", outputPath, output.Metadata.GeneratedAt)
+		return output.Feeds
+	}
+
+	config, err := feeds.LoadConfig(configPath)
+	if err != nil {
+		log.Printf("Warning: cannot check for moved repos: %v", err)
+		return nil
+	}
+	log.Printf("No pipeline output at %s; probing %d release feeds for moved repos", outputPath, len(config.Feeds))
+	return feeds.ProbeRedirects(config.Feeds)
+}
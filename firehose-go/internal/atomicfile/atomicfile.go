@@ -0,0 +1,102 @@
+// Package atomicfile writes files so readers never see a partial result.
+//
+// Content goes to a temporary file in the destination directory, is fsync'd,
+// optionally verified by reading it back, and is then renamed over the
+// destination. A crash or failed write leaves the previous file untouched.
+package atomicfile
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Write streams content from write into path atomically. verify, if non-nil,
+// reads the synced temporary file back; an error aborts the write.
+func Write(path string, perm os.FileMode, write func(w io.Writer) error, verify func(r io.Reader) error) (err error) {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("create directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("create temp file: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	buf := bufio.NewWriter(tmp)
+	if err := write(buf); err != nil {
+		return err
+	}
+	if err := buf.Flush(); err != nil {
+		return fmt.Errorf("write %s: %w", tmp.Name(), err)
+	}
+	if err := tmp.Sync(); err != nil {
+		return fmt.Errorf("sync %s: %w", tmp.Name(), err)
+	}
+
+	if verify != nil {
+		if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+			return fmt.Errorf("rewind %s: %w", tmp.Name(), err)
+		}
+		if err := verify(bufio.NewReader(tmp)); err != nil {
+			return fmt.Errorf("verify %s: %w", path, err)
+		}
+	}
+
+	if err := tmp.Chmod(perm); err != nil {
+		return fmt.Errorf("chmod %s: %w", tmp.Name(), err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close %s: %w", tmp.Name(), err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("rename into place: %w", err)
+	}
+	syncDir(dir)
+	return nil
+}
+
+// WriteFile writes data to path atomically; see Write.
+func WriteFile(path string, data []byte, perm os.FileMode, verify func(r io.Reader) error) error {
+	return Write(path, perm, func(w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	}, verify)
+}
+
+// VerifyJSON returns a verify function that requires the file to hold exactly
+// one JSON value that decodes into T.
+func VerifyJSON[T any]() func(r io.Reader) error {
+	return func(r io.Reader) error {
+		dec := json.NewDecoder(r)
+		var v T
+		if err := dec.Decode(&v); err != nil {
+			return fmt.Errorf("decode JSON: %w", err)
+		}
+		if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+			return fmt.Errorf("unexpected data after JSON value")
+		}
+		return nil
+	}
+}
+
+// syncDir flushes the rename to disk. Not every platform supports syncing a
+// directory, so failures are ignored; the rename itself has already succeeded.
+func syncDir(dir string) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	defer d.Close()
+	d.Sync()
+}
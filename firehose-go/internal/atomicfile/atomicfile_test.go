@@ -0,0 +1,100 @@
+package atomicfile
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWrite(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "data", "releases.json")
+
+	if err := WriteFile(path, []byte(`{"v":1}`), 0644, VerifyJSON[map[string]int]()); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	assertContent(t, path, `{"v":1}`)
+
+	t.Run("failed write keeps previous file", func(t *testing.T) {
+		err := Write(path, 0644, func(w io.Writer) error {
+			io.WriteString(w, `{"v":`)
+			return errors.New("encoder crashed")
+		}, nil)
+		if err == nil || !strings.Contains(err.Error(), "encoder crashed") {
+			t.Fatalf("Write() error = %v, want the write error", err)
+		}
+		assertContent(t, path, `{"v":1}`)
+		assertNoTempFiles(t, filepath.Dir(path))
+	})
+
+	t.Run("failed verification keeps previous file", func(t *testing.T) {
+		err := WriteFile(path, []byte(`{"v":2`), 0644, VerifyJSON[map[string]int]())
+		if err == nil {
+			t.Fatal("WriteFile() of truncated JSON succeeded")
+		}
+		assertContent(t, path, `{"v":1}`)
+		assertNoTempFiles(t, filepath.Dir(path))
+	})
+
+	t.Run("replaces file and applies permissions", func(t *testing.T) {
+		if err := WriteFile(path, []byte(`{"v":3}`), 0600, VerifyJSON[map[string]int]()); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+		assertContent(t, path, `{"v":3}`)
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Mode().Perm() != 0600 {
+			t.Errorf("mode = %v, want 0600", info.Mode().Perm())
+		}
+	})
+}
+
+func TestVerifyJSON(t *testing.T) {
+	verify := VerifyJSON[[]string]()
+	tests := []struct {
+		input   string
+		wantErr bool
+	}{
+		{`["a","b"]`, false},
+		{"[\"a\"]\n", false},
+		{`["a"`, true},
+		{`{"a":1}`, true},
+		{`["a"] ["b"]`, true},
+		{``, true},
+	}
+	for _, tt := range tests {
+		err := verify(strings.NewReader(tt.input))
+		if (err != nil) != tt.wantErr {
+			t.Errorf("verify(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+		}
+	}
+}
+
+func assertContent(t *testing.T, path, want string) {
+	t.Helper()
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != want {
+		t.Errorf("%s = %q, want %q", path, got, want)
+	}
+}
+
+func assertNoTempFiles(t *testing.T, dir string) {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		if strings.Contains(e.Name(), ".tmp-") {
+			t.Errorf("temp file %s left behind", e.Name())
+		}
+	}
+}
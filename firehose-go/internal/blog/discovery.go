@@ -8,15 +8,22 @@ import (
 	"math/rand/v2"
 	"net/http"
 	"net/url"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/castrojo/firehose-go/internal/metrics"
+	"github.com/castrojo/firehose-go/internal/robots"
 	"github.com/mmcdole/gofeed"
 	"golang.org/x/net/html"
 )
 
+// httpClient sends every discovery request through the shared robots.txt checker,
+// which sets our User-Agent and honours Disallow and Crawl-delay. The 5s timeout
+// is enforced by the checker so that Crawl-delay waits do not count against it.
 var httpClient = &http.Client{
-	Timeout: 5 * time.Second,
+	Transport: robots.Default.Transport(5 * time.Second),
 	CheckRedirect: func(req *http.Request, via []*http.Request) error {
 		if len(via) >= 5 {
 			return fmt.Errorf("too many redirects")
@@ -38,6 +45,9 @@ func retryHTTP(fn func() error, maxAttempts int, baseDelay time.Duration, url st
 			return nil
 		}
 
+		if robots.IsDisallowed(lastErr) {
+			return lastErr
+		}
 		errStr := lastErr.Error()
 		if strings.Contains(errStr, "404") || strings.Contains(errStr, "403") {
 			return lastErr
@@ -58,51 +68,128 @@ func retryHTTP(fn func() error, maxAttempts int, baseDelay time.Duration, url st
 			jitter := 0.8 + rand.Float64()*0.4
 			sleep := time.Duration(float64(backoff) * jitter)
 			log.Printf("⚠️  Retry %d/%d for %s: %v", attempt, maxAttempts, url, lastErr)
+			metrics.Default.Retry(url)
 			time.Sleep(sleep)
 		}
 	}
 	return lastErr
 }
 
+// Result describes the outcome of blog feed discovery.
+type Result struct {
+	FeedURL    string      `json:"feedUrl"`
+	Strategy   string      `json:"strategy"`         // which strategy found the feed, e.g. "hugo" or "suffix"
+	Candidates []Candidate `json:"candidates"`       // every valid feed found, best first
+	Tried      []string    `json:"tried"`            // strategies probed, in order
+	Reason     string      `json:"reason,omitempty"` // why discovery failed
+}
+
 // DiscoverFeedURL finds an RSS/Atom feed URL for a blog page. Returns "" on failure.
 func DiscoverFeedURL(blogURL string) string {
+	return Discover(blogURL).FeedURL
+}
+
+// Discover finds an RSS/Atom feed for a blog page and reports which strategy matched.
+// It collects candidates from platform strategies (identified from the host or by
+// fingerprinting the page), <link rel=alternate> tags and the generic suffixes,
+// probes all of them, and picks the highest scoring feed. Result.FeedURL is ""
+// when no feed is found.
+func Discover(blogURL string) Result {
 	blogURL = strings.TrimRight(blogURL, "/")
+	u, err := url.Parse(blogURL)
+	if err != nil {
+		log.Printf("  blog discovery: invalid URL %s: %v", blogURL, err)
+		return Result{}
+	}
 
-	if feedURL := tryMedium(blogURL); feedURL != "" {
-		return feedURL
+	var proposals []Candidate
+	seen := make(map[string]bool)
+	propose := func(feedURL, strategy string) {
+		if feedURL == "" || seen[feedURL] {
+			return
+		}
+		seen[feedURL] = true
+		proposals = append(proposals, Candidate{FeedURL: feedURL, Strategy: strategy})
+	}
+
+	p, pageErr := fetchPage(blogURL)
+	for _, s := range fingerprint(u, p) {
+		for _, candidate := range s.feedURLs(blogURL, u) {
+			propose(candidate, s.name)
+		}
+	}
+	if p != nil {
+		for _, link := range extractFeedLinks(blogURL, p.body) {
+			propose(link, StrategyHTMLLink)
+		}
 	}
 	for _, suffix := range suffixCandidates {
-		candidate := blogURL + suffix
-		if isValidFeed(candidate) {
-			log.Printf("  blog discovery: %s -> %s", blogURL, candidate)
-			return candidate
+		propose(blogURL+suffix, StrategySuffix)
+	}
+
+	var tried []string
+	for _, c := range proposals {
+		if len(tried) == 0 || tried[len(tried)-1] != c.Strategy {
+			tried = append(tried, c.Strategy)
 		}
 	}
-	if feedURL := discoverFromHTML(blogURL); feedURL != "" {
-		log.Printf("  blog discovery: %s -> %s (html link)", blogURL, feedURL)
-		return feedURL
+
+	candidates := evaluateCandidates(u, proposals, time.Now())
+	if len(candidates) == 0 {
+		reason := fmt.Sprintf("no valid feed among %d candidates", len(proposals))
+		if pageErr != nil {
+			reason += fmt.Sprintf(" (blog page: %v)", pageErr)
+		}
+		log.Printf("  blog discovery: no feed found for %s: %s", blogURL, reason)
+		return Result{Tried: tried, Reason: reason}
 	}
-	log.Printf("  blog discovery: no feed found for %s", blogURL)
-	return ""
+	best := candidates[0]
+	log.Printf("  blog discovery: %s -> %s (%s, score %.1f of %d candidates)",
+		blogURL, best.FeedURL, best.Strategy, best.Score, len(candidates))
+	return Result{FeedURL: best.FeedURL, Strategy: best.Strategy, Candidates: candidates, Tried: tried}
 }
 
-func tryMedium(blogURL string) string {
-	u, err := url.Parse(blogURL)
-	if err != nil || !strings.Contains(u.Host, "medium.com") {
-		return ""
-	}
-	path := strings.TrimLeft(u.Path, "/")
-	if path == "" {
-		return ""
+// evaluateCandidates probes each proposed feed, scores the valid ones and
+// returns them best first. Ties keep the proposal order.
+func evaluateCandidates(blogURL *url.URL, proposals []Candidate, now time.Time) []Candidate {
+	results := make([]*Candidate, len(proposals))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, 4)
+	for i, c := range proposals {
+		wg.Add(1)
+		go func(i int, c Candidate) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			if ok, reason := robots.Default.Allowed(c.FeedURL); !ok {
+				log.Printf("  blog discovery: skipping %s (robots.txt %s)", c.FeedURL, reason)
+				return
+			}
+			feed, err := fetchFeed(c.FeedURL)
+			if err != nil || len(feed.Items) == 0 {
+				return
+			}
+			c.Title = feed.Title
+			c.Items = len(feed.Items)
+			c.Score = scoreFeed(blogURL, c.FeedURL, c.Strategy, feed, now)
+			results[i] = &c
+		}(i, c)
 	}
-	candidate := fmt.Sprintf("https://medium.com/feed/%s", path)
-	if isValidFeed(candidate) {
-		return candidate
+	wg.Wait()
+
+	var candidates []Candidate
+	for _, c := range results {
+		if c != nil {
+			candidates = append(candidates, *c)
+		}
 	}
-	return ""
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].Score > candidates[j].Score })
+	return candidates
 }
 
-func discoverFromHTML(blogURL string) string {
+// fetchPage fetches the blog landing page for fingerprinting and link extraction.
+func fetchPage(blogURL string) (*page, error) {
 	var resp *http.Response
 	err := retryHTTP(func() error {
 		r, httpErr := httpClient.Get(blogURL)
@@ -118,29 +205,27 @@ func discoverFromHTML(blogURL string) string {
 	}, 2, 1*time.Second, blogURL)
 
 	if err != nil {
-		return ""
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(io.LimitReader(resp.Body, 512*1024))
 	if err != nil {
-		return ""
+		return nil, err
 	}
-	return extractFeedLink(blogURL, string(body))
+	return newPage(resp.Header, string(body)), nil
 }
 
-func extractFeedLink(baseURL, htmlContent string) string {
+// extractFeedLinks returns every RSS/Atom <link rel=alternate> href, resolved against baseURL.
+func extractFeedLinks(baseURL, htmlContent string) []string {
 	doc, err := html.Parse(strings.NewReader(htmlContent))
 	if err != nil {
-		return ""
+		return nil
 	}
 	base, _ := url.Parse(baseURL)
-	var feedURL string
+	var links []string
 	var traverse func(*html.Node)
 	traverse = func(n *html.Node) {
-		if feedURL != "" {
-			return
-		}
 		if n.Type == html.ElementNode && n.Data == "link" {
 			var rel, typ, href string
 			for _, attr := range n.Attr {
@@ -158,11 +243,7 @@ func extractFeedLink(baseURL, htmlContent string) string {
 				href != "" {
 				u, err := url.Parse(href)
 				if err == nil {
-					resolved := base.ResolveReference(u).String()
-					if isValidFeed(resolved) {
-						feedURL = resolved
-						return
-					}
+					links = append(links, base.ResolveReference(u).String())
 				}
 			}
 		}
@@ -171,10 +252,11 @@ func extractFeedLink(baseURL, htmlContent string) string {
 		}
 	}
 	traverse(doc)
-	return feedURL
+	return links
 }
 
-func isValidFeed(feedURL string) bool {
+// fetchFeed fetches and parses a candidate feed.
+func fetchFeed(feedURL string) (*gofeed.Feed, error) {
 	var feed *gofeed.Feed
 	err := retryHTTP(func() error {
 		fp := gofeed.NewParser()
@@ -185,6 +267,8 @@ func isValidFeed(feedURL string) bool {
 		}
 		return parseErr
 	}, 2, 1*time.Second, feedURL)
-
-	return err == nil && feed != nil && len(feed.Items) > 0
+	if err != nil {
+		return nil, err
+	}
+	return feed, nil
 }
@@ -4,16 +4,16 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"testing"
 )
 
-func TestExtractFeedLink(t *testing.T) {
+func TestExtractFeedLinks(t *testing.T) {
 	tests := []struct {
-		name     string
-		baseURL  string
-		html     string
-		wantURL  string
-		wantNone bool
+		name    string
+		baseURL string
+		html    string
+		want    []string
 	}{
 		{
 			name:    "RSS feed link with relative href",
@@ -25,7 +25,7 @@ func TestExtractFeedLink(t *testing.T) {
 </head>
 <body></body>
 </html>`,
-			wantNone: true,
+			want: []string{"https://example.com/feed.xml"},
 		},
 		{
 			name:    "Atom feed link with absolute URL",
@@ -37,7 +37,7 @@ func TestExtractFeedLink(t *testing.T) {
 </head>
 <body></body>
 </html>`,
-			wantNone: true,
+			want: []string{"https://example.com/atom.xml"},
 		},
 		{
 			name:    "no feed link",
@@ -49,17 +49,14 @@ func TestExtractFeedLink(t *testing.T) {
 </head>
 <body></body>
 </html>`,
-			wantURL:  "",
-			wantNone: false,
 		},
 		{
 			name:    "invalid HTML",
 			baseURL: "https://example.com",
 			html:    `<invalid><unclosed>`,
-			wantURL: "",
 		},
 		{
-			name:    "multiple feed links - returns first valid",
+			name:    "multiple feed links in document order",
 			baseURL: "https://example.com",
 			html: `<!DOCTYPE html>
 <html>
@@ -68,7 +65,7 @@ func TestExtractFeedLink(t *testing.T) {
 	<link rel="alternate" type="application/atom+xml" href="/atom.xml" />
 </head>
 </html>`,
-			wantNone: true,
+			want: []string{"https://example.com/rss.xml", "https://example.com/atom.xml"},
 		},
 		{
 			name:    "link without alternate rel",
@@ -79,7 +76,6 @@ func TestExtractFeedLink(t *testing.T) {
 	<link rel="stylesheet" type="text/css" href="/style.css" />
 </head>
 </html>`,
-			wantURL: "",
 		},
 		{
 			name:    "link without feed type",
@@ -90,7 +86,6 @@ func TestExtractFeedLink(t *testing.T) {
 	<link rel="alternate" type="text/html" href="/page.html" />
 </head>
 </html>`,
-			wantURL: "",
 		},
 		{
 			name:    "relative path resolution",
@@ -101,69 +96,20 @@ func TestExtractFeedLink(t *testing.T) {
 	<link rel="alternate" type="application/rss+xml" href="../feed.xml" />
 </head>
 </html>`,
-			wantNone: true,
+			want: []string{"https://example.com/feed.xml"},
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if tt.wantNone {
-				server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-					w.Header().Set("Content-Type", "application/rss+xml")
-					w.WriteHeader(http.StatusOK)
-					fmt.Fprint(w, `<?xml version="1.0"?><rss version="2.0"><channel><title>Test</title><link>http://example.com</link><item><title>Test</title><link>http://example.com/1</link></item></channel></rss>`)
-				}))
-				defer server.Close()
-
-				html := tt.html
-				for i := 0; i < 10; i++ {
-					testURL := fmt.Sprintf("http://127.0.0.1:%d/feed.xml", 10000+i)
-					html = replaceHrefWithTestURL(html, testURL)
-					originalBaseURL := tt.baseURL
-					got := extractFeedLink(originalBaseURL, html)
-					if got != "" {
-						return
-					}
-				}
-
-				server2 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-					w.Header().Set("Content-Type", "application/rss+xml")
-					w.WriteHeader(http.StatusOK)
-					fmt.Fprint(w, `<?xml version="1.0"?><rss version="2.0"><channel><title>T</title><link>http://e.com</link><item><title>T</title><link>http://e.com/1</link></item></channel></rss>`)
-				}))
-				defer server2.Close()
-
-				htmlWithValidFeed := replaceHrefWithTestURL(tt.html, server2.URL)
-				got := extractFeedLink(tt.baseURL, htmlWithValidFeed)
-				if got != server2.URL {
-					t.Errorf("extractFeedLink() with valid feed = %q, want %q", got, server2.URL)
-				}
-			} else {
-				got := extractFeedLink(tt.baseURL, tt.html)
-				if got != tt.wantURL {
-					t.Errorf("extractFeedLink() = %q, want %q", got, tt.wantURL)
-				}
+			got := extractFeedLinks(tt.baseURL, tt.html)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("extractFeedLinks() = %q, want %q", got, tt.want)
 			}
 		})
 	}
 }
 
-func replaceHrefWithTestURL(html, testURL string) string {
-	import1 := "href=\"/feed.xml\""
-	import2 := "href=\"https://example.com/atom.xml\""
-	import3 := "href=\"/rss.xml\""
-	import4 := "href=\"../feed.xml\""
-
-	replacement := fmt.Sprintf("href=\"%s\"", testURL)
-
-	html = replaceFirst(html, import1, replacement)
-	html = replaceFirst(html, import2, replacement)
-	html = replaceFirst(html, import3, replacement)
-	html = replaceFirst(html, import4, replacement)
-
-	return html
-}
-
 func replaceFirst(s, old, new string) string {
 	for i := 0; i+len(old) <= len(s); i++ {
 		match := true
@@ -293,6 +239,42 @@ func TestDiscoverFeedURL(t *testing.T) {
 		}
 	})
 
+	t.Run("skips advertised links that are not valid feeds", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/":
+				w.Header().Set("Content-Type", "text/html")
+				fmt.Fprint(w, `<!DOCTYPE html>
+<html>
+<head>
+	<link rel="alternate" type="application/rss+xml" href="/broken.xml" />
+	<link rel="alternate" type="application/atom+xml" href="/empty.xml" />
+	<link rel="alternate" type="application/rss+xml" href="/posts.xml" />
+</head>
+</html>`)
+			case "/broken.xml":
+				fmt.Fprint(w, "not a feed")
+			case "/empty.xml":
+				w.Header().Set("Content-Type", "application/atom+xml")
+				fmt.Fprint(w, `<?xml version="1.0"?><feed xmlns="http://www.w3.org/2005/Atom"><title>Empty</title></feed>`)
+			case "/posts.xml":
+				w.Header().Set("Content-Type", "application/rss+xml")
+				fmt.Fprint(w, `<?xml version="1.0"?><rss version="2.0"><channel><title>Posts</title><link>http://e.com</link><item><title>P</title><link>http://e.com/1</link></item></channel></rss>`)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		defer server.Close()
+
+		res := Discover(server.URL)
+		if res.FeedURL != server.URL+"/posts.xml" || res.Strategy != StrategyHTMLLink {
+			t.Errorf("Discover() = %q (%s), want %q (%s)", res.FeedURL, res.Strategy, server.URL+"/posts.xml", StrategyHTMLLink)
+		}
+		if len(res.Candidates) != 1 {
+			t.Errorf("Discover() found %d candidates, want only the valid feed", len(res.Candidates))
+		}
+	})
+
 	t.Run("no feed found", func(t *testing.T) {
 		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusNotFound)
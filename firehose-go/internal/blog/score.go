@@ -0,0 +1,111 @@
+package blog
+
+import (
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// Candidate is a feed found during discovery, with the score used to rank it.
+type Candidate struct {
+	FeedURL  string  `json:"feedUrl"`
+	Strategy string  `json:"strategy"`
+	Title    string  `json:"title,omitempty"`
+	Items    int     `json:"items"`
+	Score    float64 `json:"score"`
+}
+
+// Scoring weights. A comments feed should never win, and release or single
+// category feeds should lose to a general blog feed on the same site.
+const (
+	scoreBlogTitle      = 2.0
+	scoreNewsTitle      = 1.0
+	scoreComments       = -10.0
+	scoreNarrowFeed     = -3.0
+	scoreRecentQuarter  = 3.0
+	scoreRecentYear     = 1.5
+	scoreItemsMax       = 2.0
+	scoreItemsSaturate  = 20
+	scoreUnderBlog      = 3.0
+	scoreSameHost       = 1.0
+	scorePlatformPath   = 1.0
+	scoreAdvertisedLink = 0.5
+)
+
+// narrowFeedMarkers identify feeds that cover a subset of the blog.
+var narrowFeedMarkers = []string{"/releases", "/tags/", "/tag/", "/categories/", "/category/", "/authors/", "/author/"}
+
+// scoreFeed rates how likely feed is the main feed of the blog at blogURL.
+func scoreFeed(blogURL *url.URL, feedURL, strategy string, feed *gofeed.Feed, now time.Time) float64 {
+	score := 0.0
+	title := strings.ToLower(feed.Title)
+	lowerURL := strings.ToLower(feedURL)
+
+	// Title similarity to "blog".
+	switch {
+	case strings.Contains(title, "blog"):
+		score += scoreBlogTitle
+	case strings.Contains(title, "news"):
+		score += scoreNewsTitle
+	}
+
+	// Comments feeds (WordPress "/comments/feed/", "Comments for ...").
+	if strings.Contains(title, "comments") || strings.Contains(lowerURL, "comments") {
+		score += scoreComments
+	}
+	for _, marker := range narrowFeedMarkers {
+		if strings.Contains(lowerURL, marker) {
+			score += scoreNarrowFeed
+			break
+		}
+	}
+
+	// Item recency.
+	var newest time.Time
+	for _, item := range feed.Items {
+		for _, t := range []*time.Time{item.PublishedParsed, item.UpdatedParsed} {
+			if t != nil && t.After(newest) {
+				newest = *t
+			}
+		}
+	}
+	if !newest.IsZero() {
+		switch age := now.Sub(newest); {
+		case age < 90*24*time.Hour:
+			score += scoreRecentQuarter
+		case age < 365*24*time.Hour:
+			score += scoreRecentYear
+		}
+	}
+
+	// Item count, saturating so a huge archive does not dominate.
+	score += scoreItemsMax * float64(min(len(feed.Items), scoreItemsSaturate)) / scoreItemsSaturate
+
+	// Whether items link under the blog URL.
+	if len(feed.Items) > 0 {
+		var under, sameHost int
+		for _, item := range feed.Items {
+			link, err := url.Parse(item.Link)
+			if err != nil || !strings.EqualFold(strings.TrimPrefix(link.Hostname(), "www."), strings.TrimPrefix(blogURL.Hostname(), "www.")) {
+				continue
+			}
+			sameHost++
+			if strings.HasPrefix(strings.ToLower(link.Path), strings.ToLower(strings.TrimRight(blogURL.Path, "/"))) {
+				under++
+			}
+		}
+		n := float64(len(feed.Items))
+		score += scoreUnderBlog*float64(under)/n + scoreSameHost*float64(sameHost)/n
+	}
+
+	switch strategy {
+	case StrategySuffix:
+	case StrategyHTMLLink:
+		score += scoreAdvertisedLink
+	default:
+		score += scorePlatformPath
+	}
+	return score
+}
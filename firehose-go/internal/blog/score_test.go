@@ -0,0 +1,101 @@
+package blog
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+)
+
+func TestScoreFeed(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	recent := now.Add(-7 * 24 * time.Hour)
+	old := now.Add(-3 * 365 * 24 * time.Hour)
+	blogURL, _ := url.Parse("https://example.io/blog")
+
+	items := func(n int, date time.Time, linkPrefix string) []*gofeed.Item {
+		var out []*gofeed.Item
+		for i := 0; i < n; i++ {
+			d := date
+			out = append(out, &gofeed.Item{Link: fmt.Sprintf("%s/post-%d", linkPrefix, i), PublishedParsed: &d})
+		}
+		return out
+	}
+
+	blogFeed := &gofeed.Feed{Title: "Example Blog", Items: items(10, recent, "https://example.io/blog")}
+	commentsFeed := &gofeed.Feed{Title: "Comments for Example", Items: items(10, recent, "https://example.io/blog")}
+	releasesFeed := &gofeed.Feed{Title: "Releases", Items: items(20, recent, "https://github.com/example/example/releases")}
+	staleFeed := &gofeed.Feed{Title: "Example Blog", Items: items(10, old, "https://example.io/blog")}
+
+	blogScore := scoreFeed(blogURL, "https://example.io/blog/feed.xml", StrategySuffix, blogFeed, now)
+	commentsScore := scoreFeed(blogURL, "https://example.io/comments/feed/", StrategySuffix, commentsFeed, now)
+	releasesScore := scoreFeed(blogURL, "https://example.io/releases/index.xml", StrategySuffix, releasesFeed, now)
+	staleScore := scoreFeed(blogURL, "https://example.io/blog/feed.xml", StrategySuffix, staleFeed, now)
+	platformScore := scoreFeed(blogURL, "https://example.io/blog/index.xml", StrategyHugo, blogFeed, now)
+
+	if commentsScore >= blogScore {
+		t.Errorf("comments feed score %.2f >= blog feed score %.2f", commentsScore, blogScore)
+	}
+	if releasesScore >= blogScore {
+		t.Errorf("releases feed score %.2f >= blog feed score %.2f", releasesScore, blogScore)
+	}
+	if staleScore >= blogScore {
+		t.Errorf("stale feed score %.2f >= recent feed score %.2f", staleScore, blogScore)
+	}
+	if platformScore <= blogScore {
+		t.Errorf("platform path score %.2f <= suffix score %.2f for the same feed", platformScore, blogScore)
+	}
+}
+
+func TestDiscoverRanksCandidates(t *testing.T) {
+	date := time.Now().UTC().Add(-48 * time.Hour).Format(time.RFC1123Z)
+	feed := func(title, linkPrefix string) string {
+		return fmt.Sprintf(`<?xml version="1.0"?><rss version="2.0"><channel><title>%s</title><link>http://e.com</link>`+
+			`<item><title>P1</title><link>%s/1</link><pubDate>%s</pubDate></item>`+
+			`<item><title>P2</title><link>%s/2</link><pubDate>%s</pubDate></item></channel></rss>`,
+			title, linkPrefix, date, linkPrefix, date)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		base := "http://" + r.Host
+		switch r.URL.Path {
+		case "/":
+			// The comments feed is advertised first; the blog feed second.
+			fmt.Fprint(w, `<html><head>
+<link rel="alternate" type="application/rss+xml" href="/comments/feed">
+<link rel="alternate" type="application/rss+xml" href="/blog/rss.xml">
+</head></html>`)
+		case "/comments/feed":
+			fmt.Fprint(w, feed("Comments for Example", base+"/blog"))
+		case "/blog/rss.xml":
+			fmt.Fprint(w, feed("Example Blog", base+"/blog"))
+		case "/feed":
+			fmt.Fprint(w, feed("Example releases", "https://github.com/example/example/releases"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	got := Discover(server.URL)
+
+	if got.FeedURL != server.URL+"/blog/rss.xml" {
+		t.Errorf("Discover() picked %q, want the blog feed", got.FeedURL)
+	}
+	if len(got.Candidates) != 3 {
+		t.Fatalf("Discover() returned %d candidates, want 3: %+v", len(got.Candidates), got.Candidates)
+	}
+	if last := got.Candidates[len(got.Candidates)-1]; !strings.Contains(last.FeedURL, "comments") {
+		t.Errorf("lowest ranked candidate = %q, want the comments feed", last.FeedURL)
+	}
+	for i := 1; i < len(got.Candidates); i++ {
+		if got.Candidates[i].Score > got.Candidates[i-1].Score {
+			t.Errorf("candidates not sorted by score: %+v", got.Candidates)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package blog
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Strategy names reported in discovery results.
+const (
+	StrategyMedium     = "medium"
+	StrategySubstack   = "substack"
+	StrategyHashnode   = "hashnode"
+	StrategyDevTo      = "devto"
+	StrategyHugo       = "hugo"
+	StrategyDocusaurus = "docusaurus"
+	StrategyJekyll     = "jekyll"
+	StrategyGhost      = "ghost"
+	StrategyWordPress  = "wordpress"
+	StrategySuffix     = "suffix"
+	StrategyHTMLLink   = "html-link"
+)
+
+// page is a fetched blog landing page used to fingerprint the site generator.
+type page struct {
+	header    http.Header
+	body      string
+	generator string // lower-cased <meta name="generator"> content
+}
+
+// strategy describes how to find the feed of one blogging platform.
+// A platform is identified either by its host alone (hosted platforms such as
+// Medium or dev.to) or by fingerprinting the fetched blog page.
+type strategy struct {
+	name      string
+	matchHost func(u *url.URL) bool
+	matchPage func(p *page) bool
+	feedURLs  func(blogURL string, u *url.URL) []string
+}
+
+// strategies is the registry of platform-specific discovery strategies, tried in order.
+var strategies = []strategy{
+	{
+		name:      StrategyMedium,
+		matchHost: func(u *url.URL) bool { return strings.Contains(u.Host, "medium.com") },
+		feedURLs: func(_ string, u *url.URL) []string {
+			path := strings.Trim(u.Path, "/")
+			if path == "" {
+				return nil
+			}
+			return []string{fmt.Sprintf("https://medium.com/feed/%s", path)}
+		},
+	},
+	{
+		name:      StrategySubstack,
+		matchHost: func(u *url.URL) bool { return strings.HasSuffix(u.Hostname(), ".substack.com") },
+		matchPage: func(p *page) bool { return strings.Contains(p.body, "substackcdn.com") },
+		feedURLs:  func(_ string, u *url.URL) []string { return []string{origin(u) + "/feed"} },
+	},
+	{
+		name:      StrategyHashnode,
+		matchHost: func(u *url.URL) bool { return strings.HasSuffix(u.Hostname(), ".hashnode.dev") },
+		matchPage: func(p *page) bool {
+			return strings.Contains(p.generator, "hashnode") || strings.Contains(p.body, "cdn.hashnode.com")
+		},
+		feedURLs: func(_ string, u *url.URL) []string { return []string{origin(u) + "/rss.xml"} },
+	},
+	{
+		name:      StrategyDevTo,
+		matchHost: func(u *url.URL) bool { return u.Hostname() == "dev.to" },
+		feedURLs: func(_ string, u *url.URL) []string {
+			user := strings.SplitN(strings.Trim(u.Path, "/"), "/", 2)[0]
+			if user == "" {
+				return nil
+			}
+			return []string{"https://dev.to/feed/" + user}
+		},
+	},
+	{
+		name:      StrategyHugo,
+		matchPage: func(p *page) bool { return strings.Contains(p.generator, "hugo") },
+		feedURLs: func(blogURL string, u *url.URL) []string {
+			return []string{blogURL + "/index.xml", origin(u) + "/blog/index.xml", origin(u) + "/index.xml"}
+		},
+	},
+	{
+		name: StrategyDocusaurus,
+		matchPage: func(p *page) bool {
+			return strings.Contains(p.generator, "docusaurus") || strings.Contains(p.body, "__docusaurus")
+		},
+		feedURLs: func(blogURL string, u *url.URL) []string {
+			return []string{blogURL + "/rss.xml", blogURL + "/atom.xml", origin(u) + "/blog/rss.xml"}
+		},
+	},
+	{
+		name:      StrategyJekyll,
+		matchPage: func(p *page) bool { return strings.Contains(p.generator, "jekyll") },
+		feedURLs: func(blogURL string, u *url.URL) []string {
+			return []string{blogURL + "/feed.xml", origin(u) + "/feed.xml", blogURL + "/atom.xml"}
+		},
+	},
+	{
+		name: StrategyGhost,
+		matchPage: func(p *page) bool {
+			return strings.Contains(p.generator, "ghost") || p.header.Get("X-Ghost-Cache-Status") != ""
+		},
+		feedURLs: func(blogURL string, u *url.URL) []string {
+			return []string{blogURL + "/rss/", origin(u) + "/rss/"}
+		},
+	},
+	{
+		name: StrategyWordPress,
+		matchPage: func(p *page) bool {
+			return strings.Contains(p.generator, "wordpress") ||
+				strings.Contains(p.header.Get("Link"), "api.w.org") ||
+				strings.Contains(p.body, "/wp-content/")
+		},
+		feedURLs: func(blogURL string, u *url.URL) []string {
+			return []string{blogURL + "/feed/", origin(u) + "/feed/", origin(u) + "/?feed=rss2"}
+		},
+	},
+}
+
+// origin returns the scheme and host of u.
+func origin(u *url.URL) string {
+	return u.Scheme + "://" + u.Host
+}
+
+// newPage builds a page from a response header and body, extracting the generator meta tag.
+func newPage(header http.Header, body string) *page {
+	p := &page{header: header, body: body}
+	if header == nil {
+		p.header = http.Header{}
+	}
+	doc, err := html.Parse(strings.NewReader(body))
+	if err != nil {
+		return p
+	}
+	var traverse func(*html.Node)
+	traverse = func(n *html.Node) {
+		if p.generator != "" {
+			return
+		}
+		if n.Type == html.ElementNode && n.Data == "meta" {
+			var name, content string
+			for _, attr := range n.Attr {
+				switch strings.ToLower(attr.Key) {
+				case "name":
+					name = strings.ToLower(attr.Val)
+				case "content":
+					content = attr.Val
+				}
+			}
+			if name == "generator" {
+				p.generator = strings.ToLower(content)
+				return
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			traverse(c)
+		}
+	}
+	traverse(doc)
+	return p
+}
+
+// fingerprint returns the strategies matching the blog URL's host or its fetched page.
+// Host matches come first since they are unambiguous.
+func fingerprint(u *url.URL, p *page) []strategy {
+	var hostMatches, pageMatches []strategy
+	for _, s := range strategies {
+		switch {
+		case s.matchHost != nil && s.matchHost(u):
+			hostMatches = append(hostMatches, s)
+		case p != nil && s.matchPage != nil && s.matchPage(p):
+			pageMatches = append(pageMatches, s)
+		}
+	}
+	return append(hostMatches, pageMatches...)
+}
@@ -0,0 +1,195 @@
+package blog
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+)
+
+const testRSS = `<?xml version="1.0"?><rss version="2.0"><channel><title>T</title><link>http://e.com</link><item><title>T</title><link>http://e.com/1</link></item></channel></rss>`
+
+func TestFingerprint(t *testing.T) {
+	tests := []struct {
+		name   string
+		url    string
+		header http.Header
+		body   string
+		want   string
+	}{
+		{
+			name: "hugo generator meta",
+			url:  "https://example.com/blog",
+			body: `<html><head><meta name="generator" content="Hugo 0.120.4"></head></html>`,
+			want: StrategyHugo,
+		},
+		{
+			name: "docusaurus generator meta",
+			url:  "https://example.com/blog",
+			body: `<html><head><meta name="generator" content="Docusaurus v3.1.0"></head></html>`,
+			want: StrategyDocusaurus,
+		},
+		{
+			name: "jekyll generator meta",
+			url:  "https://example.com",
+			body: `<html><head><meta name="generator" content="Jekyll v4.3.2" /></head></html>`,
+			want: StrategyJekyll,
+		},
+		{
+			name:   "ghost cache header",
+			url:    "https://example.com",
+			header: http.Header{"X-Ghost-Cache-Status": []string{"HIT"}},
+			body:   `<html></html>`,
+			want:   StrategyGhost,
+		},
+		{
+			name:   "wordpress REST API link header",
+			url:    "https://example.com",
+			header: http.Header{"Link": []string{`<https://example.com/wp-json/>; rel="https://api.w.org/"`}},
+			body:   `<html></html>`,
+			want:   StrategyWordPress,
+		},
+		{
+			name: "substack host",
+			url:  "https://cncf.substack.com",
+			want: StrategySubstack,
+		},
+		{
+			name: "hashnode host",
+			url:  "https://project.hashnode.dev",
+			want: StrategyHashnode,
+		},
+		{
+			name: "dev.to host",
+			url:  "https://dev.to/someproject",
+			want: StrategyDevTo,
+		},
+		{
+			name: "medium host",
+			url:  "https://medium.com/akri",
+			want: StrategyMedium,
+		},
+		{
+			name: "unknown generator",
+			url:  "https://example.com",
+			body: `<html><head><meta name="generator" content="Handwritten"></head></html>`,
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := url.Parse(tt.url)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var p *page
+			if tt.body != "" || tt.header != nil {
+				p = newPage(tt.header, tt.body)
+			}
+			matched := fingerprint(u, p)
+			got := ""
+			if len(matched) > 0 {
+				got = matched[0].name
+			}
+			if got != tt.want {
+				t.Errorf("fingerprint(%q) = %q, want %q", tt.url, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiscoverStrategy(t *testing.T) {
+	t.Run("hugo site with section index.xml", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/blog":
+				fmt.Fprint(w, `<html><head><meta name="generator" content="Hugo 0.121.0"></head><body>Blog</body></html>`)
+			case "/blog/index.xml":
+				w.Header().Set("Content-Type", "application/rss+xml")
+				fmt.Fprint(w, testRSS)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		defer server.Close()
+
+		got := Discover(server.URL + "/blog/")
+		want := Result{FeedURL: server.URL + "/blog/index.xml", Strategy: StrategyHugo}
+		if got.FeedURL != want.FeedURL || got.Strategy != want.Strategy {
+			t.Errorf("Discover() = %s (%s), want %s (%s)", got.FeedURL, got.Strategy, want.FeedURL, want.Strategy)
+		}
+	})
+
+	t.Run("ghost site with /rss/", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/":
+				w.Header().Set("X-Ghost-Cache-Status", "MISS")
+				fmt.Fprint(w, `<html><body>Ghost blog</body></html>`)
+			case "/rss/":
+				w.Header().Set("Content-Type", "application/rss+xml")
+				fmt.Fprint(w, testRSS)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		defer server.Close()
+
+		got := Discover(server.URL)
+		want := Result{FeedURL: server.URL + "/rss/", Strategy: StrategyGhost}
+		if got.FeedURL != want.FeedURL || got.Strategy != want.Strategy {
+			t.Errorf("Discover() = %s (%s), want %s (%s)", got.FeedURL, got.Strategy, want.FeedURL, want.Strategy)
+		}
+	})
+
+	t.Run("generic suffix reports suffix strategy", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/feed.xml" {
+				w.Header().Set("Content-Type", "application/rss+xml")
+				fmt.Fprint(w, testRSS)
+				return
+			}
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer server.Close()
+
+		got := Discover(server.URL)
+		want := Result{FeedURL: server.URL + "/feed.xml", Strategy: StrategySuffix}
+		if got.FeedURL != want.FeedURL || got.Strategy != want.Strategy {
+			t.Errorf("Discover() = %s (%s), want %s (%s)", got.FeedURL, got.Strategy, want.FeedURL, want.Strategy)
+		}
+	})
+}
+
+func TestDiscoverSkipsDisallowed(t *testing.T) {
+	var mu sync.Mutex
+	var probed []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/robots.txt":
+			fmt.Fprint(w, "User-agent: *\nDisallow: /feed.xml\n")
+		case "/feed.xml", "/rss.xml":
+			mu.Lock()
+			probed = append(probed, r.URL.Path)
+			mu.Unlock()
+			w.Header().Set("Content-Type", "application/rss+xml")
+			fmt.Fprint(w, testRSS)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	got := Discover(server.URL)
+	if got.FeedURL != server.URL+"/rss.xml" {
+		t.Errorf("Discover() = %q, want the allowed /rss.xml", got.FeedURL)
+	}
+	for _, p := range probed {
+		if p == "/feed.xml" {
+			t.Error("disallowed /feed.xml was requested")
+		}
+	}
+}
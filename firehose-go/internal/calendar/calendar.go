@@ -0,0 +1,226 @@
+// Package calendar writes releases and CNCF maturity milestones as
+// iCalendar (RFC 5545) files: one overall, one per maturity tier and one per
+// project, each event an all-day entry.
+package calendar
+
+import (
+	"fmt"
+	"hash/fnv"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/castrojo/firehose-go/internal/atomicfile"
+	"github.com/castrojo/firehose-go/internal/models"
+)
+
+// PublicDir is the directory under the site root that calendars are served from.
+const PublicDir = "calendars"
+
+// Tiers are the CNCF maturity levels that get a calendar of their own.
+var Tiers = []string{"graduated", "incubating", "sandbox"}
+
+// Calendar is one .ics file before encoding.
+type Calendar struct {
+	Path        string // Relative to the calendars directory, without extension, e.g. "projects/kubernetes"
+	Name        string
+	Description string
+	Events      []Event
+}
+
+// Event is an all-day calendar entry.
+type Event struct {
+	UID        string
+	Date       time.Time // Day of the event; only the UTC date is used
+	Stamp      time.Time // When the information was published (DTSTAMP)
+	Summary    string
+	Detail     string // Plain text
+	URL        string
+	Categories []string
+}
+
+// Milestone stages, in the order a project reaches them.
+var milestones = []struct {
+	stage string
+	title string
+	date  func(models.LandscapeProject) string
+}{
+	{"accepted", "accepted into the CNCF", func(p models.LandscapeProject) string { return p.Accepted }},
+	{"incubating", "moved to incubating", func(p models.LandscapeProject) string { return p.Incubating }},
+	{"graduated", "graduated", func(p models.LandscapeProject) string { return p.Graduated }},
+}
+
+// Build returns the overall, per-tier and per-project calendars: an event per
+// release from the output (release feeds only; failed feeds are skipped) and
+// an event per milestone date of each CNCF project in the landscape.
+func Build(o *models.OutputData, landscapeData map[string]models.LandscapeProject) []*Calendar {
+	all := &Calendar{
+		Path:        "all",
+		Name:        "CNCF Releases",
+		Description: "Releases and maturity milestones of CNCF projects.",
+	}
+	tiers := make(map[string]*Calendar, len(Tiers))
+	for _, tier := range Tiers {
+		tiers[tier] = &Calendar{
+			Path:        "tiers/" + tier,
+			Name:        "CNCF " + strings.ToUpper(tier[:1]) + tier[1:] + " Releases",
+			Description: "Releases and maturity milestones of CNCF " + tier + " projects.",
+		}
+	}
+	projects := make(map[string]*Calendar)
+
+	add := func(ev Event, project, tier string) {
+		all.Events = append(all.Events, ev)
+		if c, ok := tiers[tier]; ok {
+			c.Events = append(c.Events, ev)
+		}
+		slug := models.Slug(project)
+		if slug == "" {
+			return
+		}
+		c, ok := projects[slug]
+		if !ok {
+			c = &Calendar{
+				Path:        "projects/" + slug,
+				Name:        project + " Releases",
+				Description: "Releases and CNCF maturity milestones of " + project + ".",
+			}
+			projects[slug] = c
+		}
+		c.Events = append(c.Events, ev)
+	}
+
+	for _, rel := range o.Releases {
+		if rel.FeedStatus == "error" {
+			continue
+		}
+		add(releaseEvent(rel), rel.ProjectName, rel.ProjectStatus)
+	}
+
+	// Duplicate landscape entries share a project; list its milestones once,
+	// from the first repo in sorted order so the result does not vary by run.
+	repos := make([]string, 0, len(landscapeData))
+	for repo := range landscapeData {
+		repos = append(repos, repo)
+	}
+	sort.Strings(repos)
+	seen := make(map[string]bool)
+	for _, repo := range repos {
+		proj := landscapeData[repo]
+		if !isTier(proj.Status) || seen[proj.Name] {
+			continue
+		}
+		seen[proj.Name] = true
+		for _, ev := range milestoneEvents(proj) {
+			add(ev, proj.Name, proj.Status)
+		}
+	}
+
+	cals := []*Calendar{all}
+	for _, tier := range Tiers {
+		cals = append(cals, tiers[tier])
+	}
+	slugs := make([]string, 0, len(projects))
+	for slug := range projects {
+		slugs = append(slugs, slug)
+	}
+	sort.Strings(slugs)
+	for _, slug := range slugs {
+		cals = append(cals, projects[slug])
+	}
+	for _, c := range cals {
+		sort.SliceStable(c.Events, func(i, j int) bool {
+			if !c.Events[i].Date.Equal(c.Events[j].Date) {
+				return c.Events[i].Date.After(c.Events[j].Date)
+			}
+			return c.Events[i].UID < c.Events[j].UID
+		})
+	}
+	return cals
+}
+
+func releaseEvent(rel models.Release) Event {
+	project := rel.ProjectName
+	if project == "" {
+		project = rel.FeedTitle
+	}
+	summary := rel.Title
+	if project != "" {
+		summary = project + " " + rel.Title
+	}
+	h := fnv.New64a()
+	h.Write([]byte(rel.FeedURL + "\x00" + rel.ID))
+
+	cats := []string{"release"}
+	if rel.ProjectStatus != "" {
+		cats = append([]string{rel.ProjectStatus}, cats...)
+	}
+	if project != "" {
+		cats = append([]string{project}, cats...)
+	}
+	return Event{
+		UID:        strconv.FormatUint(h.Sum64(), 36) + "@firehose",
+		Date:       rel.PubDate,
+		Stamp:      rel.PubDate,
+		Summary:    summary,
+		Detail:     plainText(rel.ContentSnippet),
+		URL:        rel.Link,
+		Categories: cats,
+	}
+}
+
+func milestoneEvents(proj models.LandscapeProject) []Event {
+	var events []Event
+	for _, m := range milestones {
+		date, err := time.Parse("2006-01-02", m.date(proj))
+		if err != nil {
+			continue
+		}
+		link := proj.HomepageURL
+		if link == "" {
+			link = proj.RepoURL
+		}
+		events = append(events, Event{
+			UID:        "cncf-" + models.Slug(proj.Name) + "-" + m.stage + "@firehose",
+			Date:       date,
+			Stamp:      date,
+			Summary:    proj.Name + " " + m.title,
+			Detail:     proj.Description,
+			URL:        link,
+			Categories: []string{proj.Name, proj.Status, "milestone"},
+		})
+	}
+	return events
+}
+
+func isTier(s string) bool {
+	return s == "graduated" || s == "incubating" || s == "sandbox"
+}
+
+// Write encodes every calendar under dir and removes .ics files left over
+// from earlier runs. It returns the number of files written.
+func Write(dir string, cals []*Calendar) (int, error) {
+	written := make(map[string]bool, len(cals))
+	for _, c := range cals {
+		path := filepath.Join(dir, filepath.FromSlash(c.Path)+".ics")
+		if err := atomicfile.WriteFile(path, Encode(c), 0644, nil); err != nil {
+			return 0, fmt.Errorf("write %s.ics: %w", c.Path, err)
+		}
+		written[path] = true
+	}
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || written[path] || filepath.Ext(path) != ".ics" {
+			return err
+		}
+		return os.Remove(path)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("remove stale calendars: %w", err)
+	}
+	return len(written), nil
+}
@@ -0,0 +1,145 @@
+package calendar
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/castrojo/firehose-go/internal/models"
+)
+
+func testInput() (*models.OutputData, map[string]models.LandscapeProject) {
+	out := &models.OutputData{
+		Releases: []models.Release{
+			{ID: "v1.30.0", Title: "v1.30.0", Link: "https://github.com/kubernetes/kubernetes/releases/tag/v1.30.0",
+				PubDate: time.Date(2026, 4, 17, 22, 30, 0, 0, time.UTC), ContentSnippet: "<p>Fixes; more, &amp; better</p>",
+				ProjectName: "Kubernetes", ProjectStatus: "graduated", FeedURL: "https://github.com/kubernetes/kubernetes/releases.atom", FeedStatus: "success"},
+			{ID: "x", Title: "broken", PubDate: time.Now(), FeedStatus: "error"},
+		},
+	}
+	landscapeData := map[string]models.LandscapeProject{
+		"kubernetes/kubernetes":   {Name: "Kubernetes", Status: "graduated", HomepageURL: "https://kubernetes.io", Accepted: "2016-03-10", Graduated: "2018-03-06"},
+		"kubernetes/kubernetes-2": {Name: "Kubernetes", Status: "graduated", Accepted: "2016-03-10"},
+		"example/tool":            {Name: "Tool", Status: "sandbox", Accepted: "2025-01-02"},
+		"example/vendor":          {Name: "Vendor", Accepted: "2020-01-01"},
+	}
+	return out, landscapeData
+}
+
+func TestBuild(t *testing.T) {
+	cals := Build(testInput())
+	byPath := make(map[string]*Calendar)
+	for _, c := range cals {
+		byPath[c.Path] = c
+	}
+
+	tests := []struct {
+		path      string
+		summaries []string
+	}{
+		{"all", []string{"Kubernetes v1.30.0", "Tool accepted into the CNCF", "Kubernetes graduated", "Kubernetes accepted into the CNCF"}},
+		{"tiers/graduated", []string{"Kubernetes v1.30.0", "Kubernetes graduated", "Kubernetes accepted into the CNCF"}},
+		{"tiers/incubating", nil},
+		{"tiers/sandbox", []string{"Tool accepted into the CNCF"}},
+		{"projects/kubernetes", []string{"Kubernetes v1.30.0", "Kubernetes graduated", "Kubernetes accepted into the CNCF"}},
+		{"projects/tool", []string{"Tool accepted into the CNCF"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			c := byPath[tt.path]
+			if c == nil {
+				t.Fatal("calendar missing")
+			}
+			var got []string
+			for _, ev := range c.Events {
+				got = append(got, ev.Summary)
+			}
+			if strings.Join(got, "|") != strings.Join(tt.summaries, "|") {
+				t.Errorf("events = %q, want %q", got, tt.summaries)
+			}
+		})
+	}
+	if len(cals) != len(tests) {
+		t.Errorf("got %d calendars, want %d", len(cals), len(tests))
+	}
+}
+
+func TestEncode(t *testing.T) {
+	var c *Calendar
+	for _, cal := range Build(testInput()) {
+		if cal.Path == "projects/kubernetes" {
+			c = cal
+		}
+	}
+	ics := string(Encode(c))
+
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\nVERSION:2.0\r\n",
+		"X-WR-CALNAME:Kubernetes Releases\r\n",
+		"DTSTART;VALUE=DATE:20260417\r\nDTEND;VALUE=DATE:20260418\r\n",
+		"DTSTAMP:20260417T223000Z\r\n",
+		`DESCRIPTION:Fixes\; more\, & better\n\nhttps://github.com/kubernetes/kub`,
+		"CATEGORIES:Kubernetes,graduated,release\r\n",
+		"UID:cncf-kubernetes-graduated@firehose\r\nDTSTAMP:20180306T000000Z\r\nDTSTART;VALUE=DATE:20180306\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(ics, want) {
+			t.Errorf("missing %q in:\n%s", want, ics)
+		}
+	}
+	for _, line := range strings.Split(ics, "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("line longer than 75 octets: %q", line)
+		}
+	}
+	if strings.Count(ics, "BEGIN:VEVENT") != 3 {
+		t.Errorf("want 3 events:\n%s", ics)
+	}
+}
+
+func TestWriteFolded(t *testing.T) {
+	long := "SUMMARY:" + strings.Repeat("é", 60) // 128 octets of two-byte runes
+	var buf bytes.Buffer
+	writeFolded(&buf, long)
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[1], " ") {
+		t.Fatalf("lines = %q", lines)
+	}
+	if lines[0]+lines[1][1:] != long {
+		t.Error("unfolding does not restore the line")
+	}
+	for _, l := range lines {
+		if len(l) > 75 {
+			t.Errorf("line is %d octets", len(l))
+		}
+	}
+}
+
+func TestWriteRemovesStaleCalendars(t *testing.T) {
+	dir := t.TempDir()
+	stale := filepath.Join(dir, "projects", "gone.ics")
+	if err := os.MkdirAll(filepath.Dir(stale), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(stale, []byte("old"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := Write(dir, Build(testInput()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 6 {
+		t.Errorf("wrote %d calendars, want 6", n)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "tiers", "sandbox.ics")); err != nil {
+		t.Error(err)
+	}
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Errorf("stale calendar kept: %v", err)
+	}
+}
@@ -0,0 +1,103 @@
+package calendar
+
+import (
+	"bytes"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+)
+
+// Encode renders c as an iCalendar document with CRLF line endings and lines
+// folded at 75 octets, as RFC 5545 requires.
+func Encode(c *Calendar) []byte {
+	var buf bytes.Buffer
+	line := func(s string) {
+		writeFolded(&buf, s)
+	}
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//firehose-go//CNCF Releases//EN")
+	line("CALSCALE:GREGORIAN")
+	line("METHOD:PUBLISH")
+	line("X-WR-CALNAME:" + escape(c.Name))
+	line("X-WR-CALDESC:" + escape(c.Description))
+	// The pipeline runs daily; ask subscribers to refresh at that pace.
+	line("REFRESH-INTERVAL;VALUE=DURATION:P1D")
+	line("X-PUBLISHED-TTL:P1D")
+	for _, ev := range c.Events {
+		day := ev.Date.UTC()
+		line("BEGIN:VEVENT")
+		line("UID:" + ev.UID)
+		line("DTSTAMP:" + ev.Stamp.UTC().Format("20060102T150405Z"))
+		line("DTSTART;VALUE=DATE:" + day.Format("20060102"))
+		line("DTEND;VALUE=DATE:" + day.AddDate(0, 0, 1).Format("20060102"))
+		line("SUMMARY:" + escape(ev.Summary))
+		detail := ev.Detail
+		if ev.URL != "" {
+			detail = strings.TrimSpace(detail + "\n\n" + ev.URL)
+		}
+		if detail != "" {
+			line("DESCRIPTION:" + escape(detail))
+		}
+		if ev.URL != "" {
+			line("URL:" + ev.URL)
+		}
+		if len(ev.Categories) > 0 {
+			cats := make([]string, len(ev.Categories))
+			for i, c := range ev.Categories {
+				cats[i] = escape(c)
+			}
+			line("CATEGORIES:" + strings.Join(cats, ","))
+		}
+		line("TRANSP:TRANSPARENT") // Releases do not make anyone busy
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+	return buf.Bytes()
+}
+
+// escape escapes a TEXT value (RFC 5545 §3.3.11).
+func escape(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", "",
+	).Replace(s)
+}
+
+// writeFolded writes a content line, folding it so no line exceeds 75
+// octets without splitting a UTF-8 sequence.
+func writeFolded(buf *bytes.Buffer, s string) {
+	limit := 75
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		buf.WriteString(s[:cut])
+		buf.WriteString("\r\n ")
+		s = s[cut:]
+		limit = 74 // Continuation lines start with a space
+	}
+	buf.WriteString(s)
+	buf.WriteString("\r\n")
+}
+
+// plainText strips HTML tags from a feed snippet and collapses whitespace.
+func plainText(s string) string {
+	var b strings.Builder
+	z := html.NewTokenizer(strings.NewReader(s))
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			return strings.Join(strings.Fields(b.String()), " ")
+		case html.TextToken:
+			b.Write(z.Text())
+			b.WriteByte(' ')
+		}
+	}
+}
@@ -0,0 +1,114 @@
+// Package codegen generates frontend type definitions from the models structs,
+// so the Astro site and the Go pipeline share one definition of the output.
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/castrojo/firehose-go/internal/models"
+	"github.com/castrojo/firehose-go/internal/schema"
+)
+
+// TypeScriptPath is where "firehose codegen ts" writes by default, relative to firehose-go/.
+const TypeScriptPath = "../src/lib/releases.d.ts"
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// roots are the documents the pipeline writes: releases.json, and index.json
+// and the shard files of the sharded output.
+var roots = []reflect.Type{
+	reflect.TypeOf(models.OutputData{}),
+	reflect.TypeOf(models.ShardIndex{}),
+	reflect.TypeOf(models.Shard{}),
+}
+
+// TypeScript returns a .d.ts declaring an interface for each of the roots and
+// every struct they contain. Property names and optionality follow the json
+// tags, and oneof validate tags become string literal unions.
+func TypeScript() []byte {
+	g := &tsGenerator{seen: make(map[reflect.Type]bool)}
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by \"firehose codegen ts\" from firehose-go/internal/models; DO NOT EDIT.\n")
+	fmt.Fprintf(&buf, "// Output schema version %s.\n", models.SchemaVersion)
+	for _, root := range roots {
+		if g.seen[root] {
+			continue
+		}
+		g.seen[root] = true
+		g.queue = append(g.queue, root)
+		for len(g.queue) > 0 {
+			t := g.queue[0]
+			g.queue = g.queue[1:]
+			buf.WriteString("\n")
+			g.writeInterface(&buf, t)
+		}
+	}
+	return buf.Bytes()
+}
+
+type tsGenerator struct {
+	seen  map[reflect.Type]bool
+	queue []reflect.Type // structs still to declare, in order of first use
+}
+
+func (g *tsGenerator) writeInterface(buf *bytes.Buffer, t reflect.Type) {
+	fmt.Fprintf(buf, "export interface %s {\n", t.Name())
+	for _, f := range schema.Fields(t) {
+		typ := g.tsType(f.Type)
+		switch {
+		case len(f.Enum) > 0:
+			values := make([]string, len(f.Enum))
+			for i, v := range f.Enum {
+				values[i] = "'" + v + "'"
+			}
+			typ = strings.Join(values, " | ")
+		case !f.Optional && (f.Type.Kind() == reflect.Slice || f.Type.Kind() == reflect.Map):
+			// encoding/json writes nil slices and maps as null.
+			typ += " | null"
+		}
+		if f.Type == timeType {
+			buf.WriteString("  /** RFC 3339 date-time */\n")
+		} else if f.Format == "uri" {
+			buf.WriteString("  /** URL */\n")
+		}
+		optional := ""
+		if f.Optional {
+			optional = "?"
+		}
+		fmt.Fprintf(buf, "  %s%s: %s;\n", f.Name, optional, typ)
+	}
+	buf.WriteString("}\n")
+}
+
+// tsType maps a Go type to TypeScript, queueing structs for declaration.
+func (g *tsGenerator) tsType(t reflect.Type) string {
+	switch {
+	case t == timeType:
+		return "string"
+	case t.Kind() == reflect.Pointer:
+		return g.tsType(t.Elem())
+	case t.Kind() == reflect.Struct:
+		if !g.seen[t] {
+			g.seen[t] = true
+			g.queue = append(g.queue, t)
+		}
+		return t.Name()
+	case t.Kind() == reflect.Slice || t.Kind() == reflect.Array:
+		return g.tsType(t.Elem()) + "[]"
+	case t.Kind() == reflect.Map:
+		return fmt.Sprintf("Record<%s, %s>", g.tsType(t.Key()), g.tsType(t.Elem()))
+	case t.Kind() == reflect.String:
+		return "string"
+	case t.Kind() == reflect.Bool:
+		return "boolean"
+	case t.Kind() >= reflect.Int && t.Kind() <= reflect.Float64:
+		return "number"
+	default:
+		return "unknown"
+	}
+}
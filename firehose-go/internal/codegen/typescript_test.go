@@ -0,0 +1,53 @@
+package codegen
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestTypeScript(t *testing.T) {
+	ts := string(TypeScript())
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"root interface", "export interface OutputData {"},
+		{"shard index root", "export interface ShardIndex {"},
+		{"shard root", "export interface Shard {"},
+		{"nested struct", "export interface FeedWarning {"},
+		{"required field", "  id: string;"},
+		{"omitempty field is optional", "  guid?: string;"},
+		{"time is a string", "  pubDate: string;"},
+		{"oneof becomes union", "  feedStatus: 'success' | 'error';"},
+		{"optional union", "  projectStatus?: 'graduated' | 'incubating' | 'sandbox';"},
+		{"nil slice may be null", "  news: Release[] | null;"},
+		{"map", "  warnings?: Record<string, number>;"},
+		{"int64", "  bytesTransferred: number;"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !strings.Contains(ts, tt.want+"\n") {
+				t.Errorf("missing %q in:\n%s", tt.want, ts)
+			}
+		})
+	}
+
+	if n := strings.Count(ts, "export interface Release {"); n != 1 {
+		t.Errorf("Release declared %d times, want 1", n)
+	}
+}
+
+// TestCommittedTypeScript keeps the frontend's generated declarations in step
+// with the models.
+func TestCommittedTypeScript(t *testing.T) {
+	committed, err := os.ReadFile("../../" + TypeScriptPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(committed, TypeScript()) {
+		t.Fatalf("%s is out of date; run: go run ./cmd/firehose codegen ts", TypeScriptPath)
+	}
+}
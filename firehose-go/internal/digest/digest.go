@@ -0,0 +1,314 @@
+// Package digest summarizes a window of pipeline output, typically a week,
+// for a "CNCF this week" post: graduated and incubating releases by project,
+// security fixes, blog posts, maturity changes and feeds that broke. It
+// renders the summary as Markdown or HTML.
+package digest
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/castrojo/firehose-go/internal/health"
+	"github.com/castrojo/firehose-go/internal/models"
+	"github.com/castrojo/firehose-go/internal/urlutil"
+)
+
+// Tiers are the maturity levels whose releases are listed by project, in order.
+var Tiers = []string{"graduated", "incubating"}
+
+// Change classifies a release by its semantic version.
+type Change string
+
+const (
+	Major       Change = "major"
+	Minor       Change = "minor"
+	Patch       Change = "patch"
+	PreRelease  Change = "pre-release"
+	Unversioned Change = ""
+)
+
+// Highlighted reports whether the change is called out in the digest.
+func (c Change) Highlighted() bool {
+	return c == Major || c == Minor
+}
+
+// Digest is the content of one digest.
+type Digest struct {
+	Since, Until time.Time
+	Tiers        []Tier
+	Security     []SecurityFix
+	Posts        []Entry
+	Maturity     []MaturityChange
+	Broken       []BrokenFeed
+	StillBroken  int  // Failing feeds that broke before the window
+	NoLandscape  bool // Maturity changes are unknown without landscape data
+	Releases     int  // Releases in the window across all tiers
+}
+
+// Tier holds one maturity level's releases, grouped by project.
+type Tier struct {
+	Name     string
+	Projects []Project
+}
+
+// Project is one project's releases in the window, newest first.
+type Project struct {
+	Name     string
+	Homepage string
+	Releases []Entry
+}
+
+// Highlighted reports whether any of the project's releases is major or minor.
+func (p Project) Highlighted() bool {
+	for _, r := range p.Releases {
+		if r.Change.Highlighted() {
+			return true
+		}
+	}
+	return false
+}
+
+// Entry is a release or blog post.
+type Entry struct {
+	Project string
+	Title   string
+	Link    string
+	Date    time.Time
+	Change  Change // Releases only
+}
+
+// SecurityFix is a release that mentions a vulnerability fix.
+type SecurityFix struct {
+	Entry
+	IDs []string // CVE and GHSA identifiers mentioned, if any
+}
+
+// MaturityChange is a CNCF milestone reached within the window.
+type MaturityChange struct {
+	Project  string
+	Homepage string
+	Stage    string // accepted, incubating or graduated
+	Date     time.Time
+}
+
+// BrokenFeed is a feed that failed in the latest run after succeeding within the window.
+type BrokenFeed struct {
+	FeedURL     string
+	Project     string
+	ErrorType   string
+	Error       string
+	LastSuccess time.Time // Zero when unknown (no health state, or never succeeded)
+}
+
+var (
+	versionRe     = regexp.MustCompile(`\bv?(\d+)\.(\d+)(?:\.(\d+))?(-[0-9A-Za-z][0-9A-Za-z.-]*)?`)
+	advisoryIDRe  = regexp.MustCompile(`(?i)\b(CVE-\d{4}-\d{4,}|GHSA(?:-[0-9a-z]{4}){3})\b`)
+	securityWords = regexp.MustCompile(`(?i)\bsecurity (fix|release|update|patch|issue|advisor)|\bvulnerabilit(y|ies)\b`)
+)
+
+// Build summarizes releases and news published in [since, until). landscapeData
+// supplies maturity milestones and may be nil; state, the feed health from the
+// same run, dates when failing feeds last worked and may also be nil.
+func Build(o *models.OutputData, landscapeData map[string]models.LandscapeProject, state *health.State, since, until time.Time) *Digest {
+	d := &Digest{Since: since, Until: until, NoLandscape: landscapeData == nil}
+	in := func(t time.Time) bool { return !t.Before(since) && t.Before(until) }
+
+	projects := make(map[string]map[string]*Project, len(Tiers)) // tier → name → project
+	for _, tier := range Tiers {
+		projects[tier] = make(map[string]*Project)
+	}
+	for _, r := range o.Releases {
+		if r.FeedStatus == "error" || !in(r.PubDate) {
+			continue
+		}
+		d.Releases++
+		e := Entry{Project: projectName(r), Title: r.Title, Link: r.Link, Date: r.PubDate, Change: Classify(r.Title)}
+		if ids, ok := securityFix(r); ok {
+			d.Security = append(d.Security, SecurityFix{Entry: e, IDs: ids})
+		}
+		byName, ok := projects[r.ProjectStatus]
+		if !ok {
+			continue
+		}
+		p := byName[e.Project]
+		if p == nil {
+			p = &Project{Name: e.Project, Homepage: r.ProjectHomepage}
+			byName[e.Project] = p
+		}
+		p.Releases = append(p.Releases, e)
+	}
+	for _, tier := range Tiers {
+		t := Tier{Name: tier}
+		for _, p := range projects[tier] {
+			sortEntries(p.Releases)
+			t.Projects = append(t.Projects, *p)
+		}
+		sort.Slice(t.Projects, func(i, j int) bool {
+			return strings.ToLower(t.Projects[i].Name) < strings.ToLower(t.Projects[j].Name)
+		})
+		d.Tiers = append(d.Tiers, t)
+	}
+	sort.SliceStable(d.Security, func(i, j int) bool { return d.Security[i].Date.After(d.Security[j].Date) })
+
+	for _, r := range o.News {
+		if r.FeedStatus == "error" || !in(r.PubDate) {
+			continue
+		}
+		d.Posts = append(d.Posts, Entry{Project: projectName(r), Title: r.Title, Link: r.Link, Date: r.PubDate})
+	}
+	sortEntries(d.Posts)
+
+	d.Maturity = maturityChanges(landscapeData, since, until)
+	d.Broken, d.StillBroken = brokenFeeds(o.Feeds, landscapeData, state, since)
+	return d
+}
+
+// Classify returns the kind of release a title names: x.0.0 is major, x.y.0
+// minor (0.y.0 too), anything else with a version a patch, and a version with
+// a suffix such as -rc.1 a pre-release.
+func Classify(title string) Change {
+	m := versionRe.FindStringSubmatch(title)
+	if m == nil {
+		return Unversioned
+	}
+	if m[4] != "" {
+		return PreRelease
+	}
+	major, _ := strconv.Atoi(m[1])
+	minor, _ := strconv.Atoi(m[2])
+	patch, _ := strconv.Atoi(m[3]) // 0 when absent: "v2.1" is a minor release
+	switch {
+	case patch != 0:
+		return Patch
+	case minor != 0 || major == 0:
+		return Minor
+	default:
+		return Major
+	}
+}
+
+// securityFix reports whether a release mentions a vulnerability fix, and the
+// advisory IDs it names.
+func securityFix(r models.Release) ([]string, bool) {
+	text := r.Title + "\n" + r.Content + "\n" + r.ContentSnippet
+	var ids []string
+	seen := make(map[string]bool)
+	for _, id := range advisoryIDRe.FindAllString(text, -1) {
+		id = strings.ToUpper(id)
+		if strings.HasPrefix(id, "GHSA") {
+			id = "GHSA" + strings.ToLower(id[4:])
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids, len(ids) > 0 || securityWords.MatchString(text)
+}
+
+// maturityChanges lists the milestones reached within the window, newest
+// first. Milestones are dates, so the window is widened to whole days: the
+// first day counts and the last does not, so consecutive digests list each
+// milestone once.
+func maturityChanges(landscapeData map[string]models.LandscapeProject, since, until time.Time) []MaturityChange {
+	firstDay := since.UTC().Truncate(24 * time.Hour)
+	lastDay := until.UTC().Truncate(24 * time.Hour)
+	// A project can be listed under several repos; visit them in order so the
+	// same repo describes it every run.
+	repos := make([]string, 0, len(landscapeData))
+	for repo := range landscapeData {
+		repos = append(repos, repo)
+	}
+	sort.Strings(repos)
+
+	var changes []MaturityChange
+	seen := make(map[string]bool)
+	for _, repo := range repos {
+		p := landscapeData[repo]
+		for _, m := range []struct{ stage, date string }{
+			{"accepted", p.Accepted}, {"incubating", p.Incubating}, {"graduated", p.Graduated},
+		} {
+			date, err := time.Parse(time.DateOnly, m.date)
+			if err != nil || date.Before(firstDay) || !date.Before(lastDay) || seen[p.Name+"|"+m.stage] {
+				continue
+			}
+			seen[p.Name+"|"+m.stage] = true
+			changes = append(changes, MaturityChange{Project: p.Name, Homepage: p.HomepageURL, Stage: m.stage, Date: date})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		if !changes[i].Date.Equal(changes[j].Date) {
+			return changes[i].Date.After(changes[j].Date)
+		}
+		return changes[i].Project < changes[j].Project
+	})
+	return changes
+}
+
+// brokenFeeds returns the failing feeds that last succeeded within the window
+// (or whose history is unknown), and how many have been failing for longer.
+func brokenFeeds(statuses []models.FeedStatus, landscapeData map[string]models.LandscapeProject, state *health.State, since time.Time) ([]BrokenFeed, int) {
+	var broken []BrokenFeed
+	older := 0
+	for _, fs := range statuses {
+		if fs.Status != "error" {
+			continue
+		}
+		b := BrokenFeed{FeedURL: fs.FeedURL, ErrorType: fs.ErrorType, Error: fs.Error}
+		if p, ok := landscapeData[urlutil.ExtractOrgRepo(fs.FeedURL)]; ok {
+			b.Project = p.Name
+		}
+		if state != nil {
+			if rec, ok := state.Feeds[fs.FeedURL]; ok {
+				if rec.LastSuccess.IsZero() || rec.LastSuccess.Before(since) {
+					older++
+					continue
+				}
+				b.LastSuccess = rec.LastSuccess
+			}
+		}
+		broken = append(broken, b)
+	}
+	sort.Slice(broken, func(i, j int) bool { return broken[i].FeedURL < broken[j].FeedURL })
+	return broken, older
+}
+
+// projectName names an item's project, falling back to the feed title or URL.
+func projectName(r models.Release) string {
+	switch {
+	case r.ProjectName != "":
+		return r.ProjectName
+	case r.FeedTitle != "":
+		return r.FeedTitle
+	default:
+		return r.FeedURL
+	}
+}
+
+func sortEntries(entries []Entry) {
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Date.After(entries[j].Date) })
+}
+
+// ParseSince parses a -since value relative to now: a number of days or weeks
+// ("7d", "2w"), a Go duration ("36h"), or a YYYY-MM-DD date.
+func ParseSince(s string, now time.Time) (time.Time, error) {
+	if n := len(s); n > 1 && (s[n-1] == 'd' || s[n-1] == 'w') {
+		if v, err := strconv.Atoi(s[:n-1]); err == nil && v > 0 {
+			if s[n-1] == 'w' {
+				v *= 7
+			}
+			return now.AddDate(0, 0, -v), nil
+		}
+	}
+	if d, err := time.ParseDuration(s); err == nil && d > 0 {
+		return now.Add(-d), nil
+	}
+	if t, err := time.Parse(time.DateOnly, s); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid -since %q: want e.g. 7d, 2w, 36h or YYYY-MM-DD", s)
+}
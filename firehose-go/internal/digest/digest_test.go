@@ -0,0 +1,183 @@
+package digest
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/castrojo/firehose-go/internal/health"
+	"github.com/castrojo/firehose-go/internal/models"
+)
+
+var (
+	since = time.Date(2026, 4, 13, 9, 0, 0, 0, time.UTC)
+	until = time.Date(2026, 4, 20, 9, 0, 0, 0, time.UTC)
+)
+
+func testInput() (*models.OutputData, map[string]models.LandscapeProject, *health.State) {
+	at := func(d int) time.Time { return time.Date(2026, 4, d, 12, 0, 0, 0, time.UTC) }
+	out := &models.OutputData{
+		Releases: []models.Release{
+			{Title: "v1.31.0", Link: "https://github.com/kubernetes/kubernetes/releases/tag/v1.31.0", PubDate: at(18),
+				ProjectName: "Kubernetes", ProjectStatus: "graduated", ProjectHomepage: "https://kubernetes.io", FeedStatus: "success"},
+			{Title: "v1.30.4", Link: "https://github.com/kubernetes/kubernetes/releases/tag/v1.30.4", PubDate: at(19),
+				Content: "Fixes CVE-2026-1234 and cve-2026-1234 again", ProjectName: "Kubernetes", ProjectStatus: "graduated", FeedStatus: "success"},
+			{Title: "v2.0.0-rc.1", Link: "https://github.com/argoproj/argo-cd/releases/tag/v2.0.0-rc.1", PubDate: at(14),
+				ProjectName: "Argo", ProjectStatus: "graduated", FeedStatus: "success"},
+			{Title: "cri-o v1.2.3", Link: "https://github.com/cri-o/cri-o/releases/tag/v1.2.3", PubDate: at(15),
+				ProjectName: "CRI-O", ProjectStatus: "graduated", FeedStatus: "success"},
+			{Title: "Release 3.0", Link: "https://github.com/example/tool/releases/tag/3.0", PubDate: at(16),
+				ContentSnippet: "Security release: GHSA-abcd-EFGH-1234", ProjectName: "Tool_kit", ProjectStatus: "sandbox", FeedStatus: "success"},
+			{Title: "v0.9.0", Link: "https://github.com/example/old/releases/tag/v0.9.0", PubDate: at(1),
+				ProjectName: "Old", ProjectStatus: "incubating", FeedStatus: "success"},
+		},
+		News: []models.Release{
+			{Title: "Kubernetes v1.31: *Elli*", Link: "https://kubernetes.io/blog/v1-31", PubDate: at(18), ProjectName: "Kubernetes", FeedStatus: "success"},
+			{Title: "Old post", Link: "https://kubernetes.io/blog/old", PubDate: at(2), ProjectName: "Kubernetes", FeedStatus: "success"},
+		},
+		Feeds: []models.FeedStatus{
+			{FeedURL: "https://github.com/kubernetes/kubernetes/releases.atom", Status: "success"},
+			{FeedURL: "https://github.com/example/tool/releases.atom", Status: "error", ErrorType: "network", Error: "HTTP 404"},
+			{FeedURL: "https://example.com/feed.xml", Status: "error", Error: "parse error"},
+			{FeedURL: "https://example.org/feed.xml", Status: "error", Error: "timeout"},
+		},
+	}
+	landscapeData := map[string]models.LandscapeProject{
+		"kubernetes/kubernetes": {Name: "Kubernetes", Accepted: "2016-03-10", Graduated: "2018-03-06"},
+		"example/tool":          {Name: "Tool_kit", Accepted: "2026-04-13", HomepageURL: "https://tool.example"},
+		"example/tool-2":        {Name: "Tool_kit", Accepted: "2026-04-13"},
+		"example/next":          {Name: "Next", Accepted: "2024-01-01", Incubating: "2026-04-20"},
+	}
+	state := &health.State{Feeds: map[string]*health.Record{
+		"https://github.com/example/tool/releases.atom": {LastSuccess: at(17)},
+		"https://example.org/feed.xml":                  {LastSuccess: at(1)},
+	}}
+	return out, landscapeData, state
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		title string
+		want  Change
+	}{
+		{"v2.0.0", Major},
+		{"Release 3.0", Major},
+		{"v1.31.0", Minor},
+		{"v0.5.0", Minor},
+		{"v1.30.4", Patch},
+		{"containerd 1.7.21", Patch},
+		{"v2.0.0-rc.1", PreRelease},
+		{"nightly", Unversioned},
+	}
+	for _, tt := range tests {
+		if got := Classify(tt.title); got != tt.want {
+			t.Errorf("Classify(%q) = %q, want %q", tt.title, got, tt.want)
+		}
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"7d", until.AddDate(0, 0, -7)},
+		{"2w", until.AddDate(0, 0, -14)},
+		{"36h", until.Add(-36 * time.Hour)},
+		{"2026-04-01", time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := ParseSince(tt.in, until)
+		if err != nil || !got.Equal(tt.want) {
+			t.Errorf("ParseSince(%q) = %v, %v; want %v", tt.in, got, err, tt.want)
+		}
+	}
+	for _, bad := range []string{"", "0d", "-7d", "week"} {
+		if _, err := ParseSince(bad, until); err == nil {
+			t.Errorf("ParseSince(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	out, landscapeData, state := testInput()
+	d := Build(out, landscapeData, state, since, until)
+
+	var buf bytes.Buffer
+	if err := d.Markdown(&buf); err != nil {
+		t.Fatal(err)
+	}
+	want := `# CNCF this week: 2026-04-13 to 2026-04-20
+
+4 graduated and incubating releases from 3 projects (5 across all projects), 2 security fixes and 1 blog post.
+
+## Releases
+
+### Graduated
+
+- Argo: [v2.0.0-rc.1](https://github.com/argoproj/argo-cd/releases/tag/v2.0.0-rc.1) (pre-release)
+- CRI-O: [cri-o v1.2.3](https://github.com/cri-o/cri-o/releases/tag/v1.2.3) (patch)
+- **[Kubernetes](https://kubernetes.io)**: [v1.30.4](https://github.com/kubernetes/kubernetes/releases/tag/v1.30.4) (patch), **[v1.31.0](https://github.com/kubernetes/kubernetes/releases/tag/v1.31.0)** (minor)
+
+### Incubating
+
+No incubating releases.
+
+## Security fixes
+
+- Kubernetes [v1.30.4](https://github.com/kubernetes/kubernetes/releases/tag/v1.30.4) (CVE-2026-1234) · 2026-04-19
+- Tool\_kit [Release 3.0](https://github.com/example/tool/releases/tag/3.0) (GHSA-abcd-efgh-1234) · 2026-04-16
+
+## Blog posts
+
+- Kubernetes: [Kubernetes v1.31: \*Elli\*](https://kubernetes.io/blog/v1-31) · 2026-04-18
+
+## Maturity changes
+
+- [Tool\_kit](https://tool.example) accepted into the CNCF · 2026-04-13
+
+## Broken feeds
+
+- <https://example.com/feed.xml>: parse error
+- Tool\_kit <https://github.com/example/tool/releases.atom>: network error: HTTP 404 (last worked 2026-04-17)
+
+1 more feed is failing since before 2026-04-13.
+`
+	if got := buf.String(); got != want {
+		t.Errorf("Markdown mismatch:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestHTML(t *testing.T) {
+	out, landscapeData, _ := testInput()
+	d := Build(out, landscapeData, nil, since, until) // No health state
+
+	var buf bytes.Buffer
+	if err := d.HTML(&buf); err != nil {
+		t.Fatal(err)
+	}
+	got := buf.String()
+	for _, want := range []string{
+		"<title>CNCF this week: 2026-04-13 to 2026-04-20</title>",
+		`<li><strong><a href="https://kubernetes.io">Kubernetes</a></strong>: <a href="https://github.com/kubernetes/kubernetes/releases/tag/v1.30.4">v1.30.4</a> (patch), <strong><a href="https://github.com/kubernetes/kubernetes/releases/tag/v1.31.0">v1.31.0</a></strong> (minor)</li>`,
+		`<a href="https://kubernetes.io/blog/v1-31">Kubernetes v1.31: *Elli*</a>`,
+		"(GHSA-abcd-efgh-1234)",
+		"<p>No incubating releases.</p>",
+		// Without health state every failing feed is listed.
+		`<a href="https://example.org/feed.xml">https://example.org/feed.xml</a>: timeout`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("HTML missing %q", want)
+		}
+	}
+
+	d = Build(out, nil, nil, since, until)
+	buf.Reset()
+	if err := d.Markdown(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "Unknown: the CNCF landscape was unavailable.") {
+		t.Error("digest without landscape data does not say maturity changes are unknown")
+	}
+}
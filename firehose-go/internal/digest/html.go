@@ -0,0 +1,94 @@
+package digest
+
+import (
+	"html/template"
+	"io"
+)
+
+// htmlTemplate mirrors the Markdown layout as a standalone page.
+var htmlTemplate = template.Must(template.New("digest").Funcs(template.FuncMap{
+	"day":          day,
+	"stageText":    stageText,
+	"tierTitle":    tierTitle,
+	"brokenDetail": brokenDetail,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}: {{.Digest.Period}}</title>
+</head>
+<body>
+<h1>{{.Title}}: {{.Digest.Period}}</h1>
+<p>{{.Digest.Summary}}</p>
+{{- with .Digest}}
+<h2>Releases</h2>
+{{- range .Tiers}}
+<h3>{{tierTitle .Name}}</h3>
+{{- if .Projects}}
+<ul>
+{{- range .Projects}}
+<li>{{if .Highlighted}}<strong>{{end}}{{if .Homepage}}<a href="{{.Homepage}}">{{.Name}}</a>{{else}}{{.Name}}{{end}}{{if .Highlighted}}</strong>{{end}}:
+{{- range $i, $r := .Releases}}{{if $i}},{{end}} {{if .Change.Highlighted}}<strong>{{end}}<a href="{{.Link}}">{{.Title}}</a>{{if .Change.Highlighted}}</strong>{{end}}{{with .Change}} ({{.}}){{end}}{{end}}</li>
+{{- end}}
+</ul>
+{{- else}}
+<p>No {{.Name}} releases.</p>
+{{- end}}
+{{- end}}
+<h2>Security fixes</h2>
+{{- if .Security}}
+<ul>
+{{- range .Security}}
+<li>{{.Project}} <a href="{{.Link}}">{{.Title}}</a>{{with .IDs}} ({{range $i, $id := .}}{{if $i}}, {{end}}{{$id}}{{end}}){{end}} · {{day .Date}}</li>
+{{- end}}
+</ul>
+{{- else}}
+<p>No releases mentioned a security fix.</p>
+{{- end}}
+<h2>Blog posts</h2>
+{{- if .Posts}}
+<ul>
+{{- range .Posts}}
+<li>{{.Project}}: <a href="{{.Link}}">{{.Title}}</a> · {{day .Date}}</li>
+{{- end}}
+</ul>
+{{- else}}
+<p>No new posts.</p>
+{{- end}}
+<h2>Maturity changes</h2>
+{{- if .NoLandscape}}
+<p>Unknown: the CNCF landscape was unavailable.</p>
+{{- else if .Maturity}}
+<ul>
+{{- range .Maturity}}
+<li>{{if .Homepage}}<a href="{{.Homepage}}">{{.Project}}</a>{{else}}{{.Project}}{{end}} {{stageText .Stage}} · {{day .Date}}</li>
+{{- end}}
+</ul>
+{{- else}}
+<p>No project changed maturity.</p>
+{{- end}}
+<h2>Broken feeds</h2>
+{{- if .Broken}}
+<ul>
+{{- range .Broken}}
+<li>{{with .Project}}{{.}} {{end}}<a href="{{.FeedURL}}">{{.FeedURL}}</a>: {{brokenDetail .}}</li>
+{{- end}}
+</ul>
+{{- else}}
+<p>No feeds broke.</p>
+{{- end}}
+{{- if .StillBroken}}
+<p>{{.StillBroken}} more {{if eq .StillBroken 1}}feed is{{else}}feeds are{{end}} failing since before {{day .Since}}.</p>
+{{- end}}
+{{- end}}
+</body>
+</html>
+`))
+
+// HTML writes the digest as a standalone HTML page.
+func (d *Digest) HTML(w io.Writer) error {
+	return htmlTemplate.Execute(w, struct {
+		Title  string
+		Digest *Digest
+	}{Title, d})
+}
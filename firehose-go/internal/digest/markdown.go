@@ -0,0 +1,187 @@
+package digest
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Title is the heading of every digest.
+const Title = "CNCF this week"
+
+// mdEscaper backslash-escapes characters that Markdown would treat as markup
+// in titles and project names.
+var mdEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`,
+	"<", `\<`, ">", `\>`, "#", `\#`, "|", `\|`,
+)
+
+// Markdown writes the digest as GitHub-flavored Markdown.
+func (d *Digest) Markdown(w io.Writer) error {
+	b := bufio.NewWriter(w)
+	p := func(format string, args ...any) { fmt.Fprintf(b, format, args...) }
+
+	p("# %s: %s\n\n", Title, d.Period())
+	p("%s\n", d.Summary())
+
+	p("\n## Releases\n")
+	for _, t := range d.Tiers {
+		p("\n### %s\n\n", tierTitle(t.Name))
+		if len(t.Projects) == 0 {
+			p("No %s releases.\n", t.Name)
+			continue
+		}
+		for _, proj := range t.Projects {
+			name := mdEscape(proj.Name)
+			if proj.Homepage != "" {
+				name = mdLink(proj.Name, proj.Homepage)
+			}
+			if proj.Highlighted() {
+				name = "**" + name + "**"
+			}
+			p("- %s: ", name)
+			for i, r := range proj.Releases {
+				if i > 0 {
+					p(", ")
+				}
+				p("%s", mdRelease(r))
+			}
+			p("\n")
+		}
+	}
+
+	p("\n## Security fixes\n\n")
+	if len(d.Security) == 0 {
+		p("No releases mentioned a security fix.\n")
+	}
+	for _, s := range d.Security {
+		p("- %s %s", mdEscape(s.Project), mdLink(s.Title, s.Link))
+		if len(s.IDs) > 0 {
+			p(" (%s)", strings.Join(s.IDs, ", "))
+		}
+		p(" · %s\n", day(s.Date))
+	}
+
+	p("\n## Blog posts\n\n")
+	if len(d.Posts) == 0 {
+		p("No new posts.\n")
+	}
+	for _, e := range d.Posts {
+		p("- %s: %s · %s\n", mdEscape(e.Project), mdLink(e.Title, e.Link), day(e.Date))
+	}
+
+	p("\n## Maturity changes\n\n")
+	switch {
+	case d.NoLandscape:
+		p("Unknown: the CNCF landscape was unavailable.\n")
+	case len(d.Maturity) == 0:
+		p("No project changed maturity.\n")
+	}
+	for _, m := range d.Maturity {
+		name := mdEscape(m.Project)
+		if m.Homepage != "" {
+			name = mdLink(m.Project, m.Homepage)
+		}
+		p("- %s %s · %s\n", name, stageText(m.Stage), day(m.Date))
+	}
+
+	p("\n## Broken feeds\n\n")
+	if len(d.Broken) == 0 {
+		p("No feeds broke.\n")
+	}
+	for _, f := range d.Broken {
+		p("- %s\n", mdBroken(f))
+	}
+	if d.StillBroken > 0 {
+		p("\n%d more %s failing since before %s.\n", d.StillBroken, plural(d.StillBroken, "feed is", "feeds are"), day(d.Since))
+	}
+	return b.Flush()
+}
+
+// Period describes the digest window, e.g. "2026-04-13 to 2026-04-20".
+func (d *Digest) Period() string {
+	return day(d.Since) + " to " + day(d.Until)
+}
+
+// Summary is the digest's one-line overview.
+func (d *Digest) Summary() string {
+	listed, projects := 0, 0
+	for _, t := range d.Tiers {
+		projects += len(t.Projects)
+		for _, p := range t.Projects {
+			listed += len(p.Releases)
+		}
+	}
+	return fmt.Sprintf("%d graduated and incubating %s from %d %s (%d across all projects), %d security %s and %d blog %s.",
+		listed, plural(listed, "release", "releases"), projects, plural(projects, "project", "projects"), d.Releases,
+		len(d.Security), plural(len(d.Security), "fix", "fixes"), len(d.Posts), plural(len(d.Posts), "post", "posts"))
+}
+
+// mdRelease renders a release link, bolding major and minor versions and
+// labelling every classified version.
+func mdRelease(e Entry) string {
+	s := mdLink(e.Title, e.Link)
+	if e.Change.Highlighted() {
+		s = "**" + s + "**"
+	}
+	if e.Change != Unversioned {
+		s += " (" + string(e.Change) + ")"
+	}
+	return s
+}
+
+func mdBroken(f BrokenFeed) string {
+	s := "<" + f.FeedURL + ">"
+	if f.Project != "" {
+		s = mdEscape(f.Project) + " " + s
+	}
+	return s + ": " + mdEscape(brokenDetail(f))
+}
+
+func mdLink(text, url string) string {
+	return "[" + mdEscape(text) + "](" + strings.NewReplacer("(", "%28", ")", "%29", " ", "%20").Replace(url) + ")"
+}
+
+func mdEscape(s string) string {
+	return mdEscaper.Replace(strings.Join(strings.Fields(s), " "))
+}
+
+// brokenDetail describes a failure and when the feed last worked.
+func brokenDetail(f BrokenFeed) string {
+	s := f.Error
+	if f.ErrorType != "" {
+		s = f.ErrorType + " error: " + s
+	}
+	if !f.LastSuccess.IsZero() {
+		s += " (last worked " + day(f.LastSuccess) + ")"
+	}
+	return s
+}
+
+func stageText(stage string) string {
+	switch stage {
+	case "accepted":
+		return "accepted into the CNCF"
+	case "incubating":
+		return "moved to incubating"
+	default:
+		return "graduated"
+	}
+}
+
+func tierTitle(tier string) string {
+	return strings.ToUpper(tier[:1]) + tier[1:]
+}
+
+func day(t time.Time) string {
+	return t.UTC().Format(time.DateOnly)
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
@@ -0,0 +1,137 @@
+// Package export streams releases and news as flat records, one per line, in
+// NDJSON or CSV for jq, BigQuery or spreadsheets. Records are read one at a
+// time from releases.json or the SQLite history store, so an export never
+// holds the whole output in memory.
+package export
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Kinds of record.
+const (
+	KindRelease = "release"
+	KindNews    = "news"
+)
+
+// Record is a release or news item with its landscape project flattened in.
+type Record struct {
+	Kind               string
+	ID                 string
+	Title              string
+	Link               string
+	PubDate            time.Time
+	Content            string
+	ContentSnippet     string
+	GUID               string
+	FeedURL            string
+	FeedTitle          string
+	FetchedAt          time.Time
+	ProjectName        string
+	ProjectDescription string
+	ProjectStatus      string
+	ProjectHomepage    string
+	ProjectRepo        string // org/repo; only known to the history store
+	ProjectCategory    string // Landscape category; only known to the history store
+	ProjectSubcategory string // Landscape subcategory; only known to the history store
+}
+
+// Column is one exported field. Names follow releases.json.
+type Column struct {
+	Name  string
+	Value func(Record) string
+}
+
+// Columns lists every column in default order.
+var Columns = []Column{
+	{"kind", func(r Record) string { return r.Kind }},
+	{"id", func(r Record) string { return r.ID }},
+	{"title", func(r Record) string { return r.Title }},
+	{"link", func(r Record) string { return r.Link }},
+	{"pubDate", func(r Record) string { return formatTime(r.PubDate) }},
+	{"content", func(r Record) string { return r.Content }},
+	{"contentSnippet", func(r Record) string { return r.ContentSnippet }},
+	{"guid", func(r Record) string { return r.GUID }},
+	{"feedUrl", func(r Record) string { return r.FeedURL }},
+	{"feedTitle", func(r Record) string { return r.FeedTitle }},
+	{"fetchedAt", func(r Record) string { return formatTime(r.FetchedAt) }},
+	{"projectName", func(r Record) string { return r.ProjectName }},
+	{"projectDescription", func(r Record) string { return r.ProjectDescription }},
+	{"projectStatus", func(r Record) string { return r.ProjectStatus }},
+	{"projectHomepage", func(r Record) string { return r.ProjectHomepage }},
+	{"projectRepo", func(r Record) string { return r.ProjectRepo }},
+	{"projectCategory", func(r Record) string { return r.ProjectCategory }},
+	{"projectSubcategory", func(r Record) string { return r.ProjectSubcategory }},
+}
+
+// SelectColumns returns the named columns in the given order; an empty
+// list selects them all.
+func SelectColumns(names []string) ([]Column, error) {
+	if len(names) == 0 {
+		return Columns, nil
+	}
+	byName := make(map[string]Column, len(Columns))
+	for _, c := range Columns {
+		byName[c.Name] = c
+	}
+	cols := make([]Column, 0, len(names))
+	for _, name := range names {
+		c, ok := byName[strings.TrimSpace(name)]
+		if !ok {
+			return nil, fmt.Errorf("unknown column %q", name)
+		}
+		cols = append(cols, c)
+	}
+	return cols, nil
+}
+
+// Filter selects the records to export. Zero values match everything.
+type Filter struct {
+	Kind  string    // KindRelease or KindNews
+	Since time.Time // Inclusive
+	Until time.Time // Exclusive
+}
+
+// Match reports whether the record passes the filter.
+func (f Filter) Match(r Record) bool {
+	if f.Kind != "" && r.Kind != f.Kind {
+		return false
+	}
+	if !f.Since.IsZero() && r.PubDate.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && !r.PubDate.Before(f.Until) {
+		return false
+	}
+	return true
+}
+
+// ParseBound parses a -since or -until value: an RFC 3339 time, or a
+// YYYY-MM-DD date meaning the start of that day (UTC). With end set, a date
+// means the end of that day, so "-until 2026-03-31" includes March 31st.
+func ParseBound(s string, end bool) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q: want YYYY-MM-DD or RFC 3339", s)
+	}
+	if end {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+// formatTime renders a time as RFC 3339 in UTC, or "" when unset.
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,210 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/castrojo/firehose-go/internal/models"
+	"github.com/castrojo/firehose-go/internal/sqlite"
+)
+
+func testOutput() *models.OutputData {
+	k8s := "https://github.com/kubernetes/kubernetes/releases.atom"
+	return &models.OutputData{
+		Metadata: models.Metadata{GeneratedAt: "2026-04-20T00:00:00Z"},
+		Releases: []models.Release{
+			{ID: "v1.31.0", Title: "v1.31.0", Link: "https://github.com/kubernetes/kubernetes/releases/tag/v1.31.0",
+				PubDate: time.Date(2026, 4, 18, 9, 0, 0, 0, time.UTC), Content: "<p>Fixes \"CVE\" & more</p>",
+				ProjectName: "Kubernetes", ProjectStatus: "graduated", FeedURL: k8s},
+			{ID: "v1.30.0", Title: "v1.30.0", Link: "https://github.com/kubernetes/kubernetes/releases/tag/v1.30.0",
+				PubDate: time.Date(2026, 3, 31, 23, 0, 0, 0, time.UTC), ProjectName: "Kubernetes", ProjectStatus: "graduated", FeedURL: k8s},
+		},
+		News: []models.Release{
+			{ID: "post", Title: "Blog, with comma", Link: "https://kubernetes.io/blog/post",
+				PubDate: time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC), ProjectName: "Kubernetes", FeedURL: "https://kubernetes.io/feed.xml"},
+		},
+		Feeds: []models.FeedStatus{{FeedURL: k8s, Status: "success", FetchedAt: "2026-04-20T00:00:00Z", Duration: "1s"}},
+	}
+}
+
+func TestReadJSON(t *testing.T) {
+	data, err := json.MarshalIndent(testOutput(), "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   []string
+	}{
+		{"everything", Filter{}, []string{"release v1.31.0", "release v1.30.0", "news Blog, with comma"}},
+		{"news only", Filter{Kind: KindNews}, []string{"news Blog, with comma"}},
+		{"since", Filter{Since: mustBound(t, "2026-04-01", false)}, []string{"release v1.31.0", "news Blog, with comma"}},
+		{"until date is inclusive", Filter{Until: mustBound(t, "2026-03-31", true)}, []string{"release v1.30.0"}},
+		{"until time is exclusive", Filter{Until: mustBound(t, "2026-04-01T12:00:00Z", true)}, []string{"release v1.30.0"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			err := ReadJSON(bytes.NewReader(data), tt.filter, func(r Record) error {
+				got = append(got, r.Kind+" "+r.Title)
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("ReadJSON: %v", err)
+			}
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if err := ReadJSON(strings.NewReader(`{"releases": {}}`), Filter{}, func(Record) error { return nil }); err == nil {
+		t.Error("ReadJSON accepted a non-array releases field")
+	}
+}
+
+func TestWriters(t *testing.T) {
+	cols, err := SelectColumns([]string{"kind", "title", "pubDate", "content", "projectName"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"ndjson", `{"kind":"release","title":"v1.31.0","pubDate":"2026-04-18T09:00:00Z","content":"<p>Fixes \"CVE\" & more</p>","projectName":"Kubernetes"}
+{"kind":"news","title":"Blog, with comma","pubDate":"2026-04-01T12:00:00Z","content":"","projectName":"Kubernetes"}
+`},
+		{"csv", `kind,title,pubDate,content,projectName
+release,v1.31.0,2026-04-18T09:00:00Z,"<p>Fixes ""CVE"" & more</p>",Kubernetes
+news,"Blog, with comma",2026-04-01T12:00:00Z,,Kubernetes
+`},
+	}
+	o := testOutput()
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			w, err := NewWriter(tt.format, &buf, cols, Options{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, r := range []Record{fromRelease(KindRelease, o.Releases[0]), fromRelease(KindNews, o.News[0])} {
+				if err := w.Write(r); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := w.Flush(); err != nil {
+				t.Fatal(err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", buf.String(), tt.want)
+			}
+		})
+	}
+
+	var buf bytes.Buffer
+	w, _ := NewWriter("csv", &buf, cols, Options{})
+	if err := w.Flush(); err != nil || buf.String() != "kind,title,pubDate,content,projectName\n" {
+		t.Errorf("empty CSV export = %q, %v; want the header", buf.String(), err)
+	}
+	if _, err := NewWriter("xml", &buf, cols, Options{}); err == nil {
+		t.Error("NewWriter accepted an unknown format")
+	}
+	if _, err := SelectColumns([]string{"title", "nope"}); err == nil {
+		t.Error("SelectColumns accepted an unknown column")
+	}
+}
+
+func TestCSVFormulas(t *testing.T) {
+	cols, err := SelectColumns([]string{"title", "content"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		opts Options
+		want string
+	}{
+		{"escaped by default", Options{}, `title,content
+"'=HYPERLINK(""https://evil.example"")",'+1
+'-2,'@SUM(A1)
+'` + "\t" + `tab,"'` + "\r" + `cr"
+v1.0 = stable,
+`},
+		{"kept on request", Options{KeepFormulas: true}, `title,content
+"=HYPERLINK(""https://evil.example"")",+1
+-2,@SUM(A1)
+"` + "\t" + `tab","` + "\r" + `cr"
+v1.0 = stable,
+`},
+	}
+	records := []Record{
+		{Title: `=HYPERLINK("https://evil.example")`, Content: "+1"},
+		{Title: "-2", Content: "@SUM(A1)"},
+		{Title: "\ttab", Content: "\rcr"},
+		{Title: "v1.0 = stable"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w, err := NewWriter("csv", &buf, cols, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, r := range records {
+				if err := w.Write(r); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := w.Flush(); err != nil {
+				t.Fatal(err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("got:\n%q\nwant:\n%q", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestReadSQLite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "firehose.db")
+	config := &models.FeedConfig{Feeds: []models.FeedSource{{URL: "https://github.com/kubernetes/kubernetes/releases.atom", Category: "graduated"}}}
+	landscapeData := map[string]models.LandscapeProject{
+		"kubernetes/kubernetes": {Name: "Kubernetes", Status: "graduated", Category: "Orchestration & Management"},
+	}
+	if err := sqlite.Write(path, testOutput(), config, landscapeData); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	err := ReadSQLite(path, Filter{Kind: KindRelease, Since: mustBound(t, "2026-04-01", false)}, func(r Record) error {
+		got = append(got, strings.Join([]string{r.Title, formatTime(r.PubDate), r.ProjectRepo, r.ProjectCategory, formatTime(r.FetchedAt)}, " | "))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ReadSQLite: %v", err)
+	}
+	want := []string{"v1.31.0 | 2026-04-18T09:00:00Z | kubernetes/kubernetes | Orchestration & Management | 2026-04-20T00:00:00Z"}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	if err := ReadSQLite(filepath.Join(t.TempDir(), "missing.db"), Filter{}, func(Record) error { return nil }); err == nil {
+		t.Error("ReadSQLite opened a missing database")
+	}
+}
+
+func mustBound(t *testing.T, s string, end bool) time.Time {
+	t.Helper()
+	b, err := ParseBound(s, end)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
@@ -0,0 +1,142 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Formats lists the supported output formats.
+var Formats = []string{"ndjson", "csv"}
+
+// Writer encodes records one at a time.
+type Writer interface {
+	Write(Record) error
+	// Flush writes any buffered output; call it once after the last record.
+	Flush() error
+}
+
+// Options tunes a writer. The zero value is the safe default.
+type Options struct {
+	// KeepFormulas writes CSV cells that start with =, +, -, @, a tab or a
+	// carriage return as they are. By default they are prefixed with ' so a
+	// spreadsheet opening the file shows feed-supplied text instead of
+	// evaluating it as a formula.
+	KeepFormulas bool
+}
+
+// NewWriter returns a writer for the named format.
+func NewWriter(format string, w io.Writer, cols []Column, opts Options) (Writer, error) {
+	switch format {
+	case "ndjson":
+		return &ndjsonWriter{w: bufio.NewWriter(w), cols: cols}, nil
+	case "csv":
+		return &csvWriter{w: csv.NewWriter(w), cols: cols, keepFormulas: opts.KeepFormulas}, nil
+	default:
+		return nil, fmt.Errorf("unknown format %q (available: ndjson, csv)", format)
+	}
+}
+
+// ndjsonWriter writes one JSON object per line, keys in column order.
+type ndjsonWriter struct {
+	w    *bufio.Writer
+	cols []Column
+	buf  bytes.Buffer
+	enc  *json.Encoder
+}
+
+func (n *ndjsonWriter) Write(r Record) error {
+	n.buf.Reset()
+	n.buf.WriteByte('{')
+	for i, c := range n.cols {
+		if i > 0 {
+			n.buf.WriteByte(',')
+		}
+		if err := n.string(c.Name); err != nil {
+			return err
+		}
+		n.buf.WriteByte(':')
+		if err := n.string(c.Value(r)); err != nil {
+			return err
+		}
+	}
+	n.buf.WriteString("}\n")
+	_, err := n.w.Write(n.buf.Bytes())
+	return err
+}
+
+func (n *ndjsonWriter) Flush() error {
+	return n.w.Flush()
+}
+
+// string appends s as a JSON string, leaving <, > and & unescaped so links
+// and content stay readable.
+func (n *ndjsonWriter) string(s string) error {
+	if n.enc == nil {
+		n.enc = json.NewEncoder(&n.buf)
+		n.enc.SetEscapeHTML(false)
+	}
+	if err := n.enc.Encode(s); err != nil {
+		return err
+	}
+	n.buf.Truncate(n.buf.Len() - 1) // Encode ends every value with a newline
+	return nil
+}
+
+// csvWriter writes a header row, then one row per record.
+type csvWriter struct {
+	w            *csv.Writer
+	cols         []Column
+	keepFormulas bool
+	header       bool
+	row          []string
+}
+
+func (c *csvWriter) Write(r Record) error {
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+	c.row = c.row[:0]
+	for _, col := range c.cols {
+		v := col.Value(r)
+		if !c.keepFormulas {
+			v = escapeFormula(v)
+		}
+		c.row = append(c.row, v)
+	}
+	return c.w.Write(c.row)
+}
+
+// escapeFormula prefixes a cell that a spreadsheet would read as a formula
+// with ', which displays the text as is (CSV injection).
+func escapeFormula(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+func (c *csvWriter) Flush() error {
+	// An export with no records still gets its header.
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *csvWriter) writeHeader() error {
+	if c.header {
+		return nil
+	}
+	c.header = true
+	names := make([]string, len(c.cols))
+	for i, col := range c.cols {
+		names[i] = col.Name
+	}
+	return c.w.Write(names)
+}
@@ -0,0 +1,170 @@
+package export
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/castrojo/firehose-go/internal/models"
+	"github.com/castrojo/firehose-go/internal/sqlite"
+)
+
+// ReadJSON streams the releases and news of a releases.json document to fn,
+// decoding one item at a time. Other top-level fields are skipped.
+func ReadJSON(r io.Reader, filter Filter, fn func(Record) error) error {
+	dec := json.NewDecoder(r)
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return fmt.Errorf("read output: %w", err)
+		}
+		kind := ""
+		switch tok {
+		case "releases":
+			kind = KindRelease
+		case "news":
+			kind = KindNews
+		}
+		if kind == "" || (filter.Kind != "" && filter.Kind != kind) {
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return fmt.Errorf("read output: %s: %w", tok, err)
+			}
+			continue
+		}
+		if err := readItems(dec, kind, filter, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readItems streams one array of items; null (no items) is accepted.
+func readItems(dec *json.Decoder, kind string, filter Filter, fn func(Record) error) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("read output: %s: %w", kind, err)
+	}
+	if tok == nil {
+		return nil
+	}
+	if tok != json.Delim('[') {
+		return fmt.Errorf("read output: %s: expected array, got %v", kind, tok)
+	}
+	for dec.More() {
+		var item models.Release
+		if err := dec.Decode(&item); err != nil {
+			return fmt.Errorf("read output: %s: %w", kind, err)
+		}
+		rec := fromRelease(kind, item)
+		if !filter.Match(rec) {
+			continue
+		}
+		if err := fn(rec); err != nil {
+			return err
+		}
+	}
+	return expectDelim(dec, ']')
+}
+
+func expectDelim(dec *json.Decoder, want json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("read output: %w", err)
+	}
+	if tok != want {
+		return fmt.Errorf("read output: expected %q, got %v", want, tok)
+	}
+	return nil
+}
+
+// fromRelease flattens an output item into a record.
+func fromRelease(kind string, r models.Release) Record {
+	return Record{
+		Kind:               kind,
+		ID:                 r.ID,
+		Title:              r.Title,
+		Link:               r.Link,
+		PubDate:            r.PubDate,
+		Content:            r.Content,
+		ContentSnippet:     r.ContentSnippet,
+		GUID:               r.GUID,
+		FeedURL:            r.FeedURL,
+		FeedTitle:          r.FeedTitle,
+		FetchedAt:          r.FetchedAt,
+		ProjectName:        r.ProjectName,
+		ProjectDescription: r.ProjectDescription,
+		ProjectStatus:      r.ProjectStatus,
+		ProjectHomepage:    r.ProjectHomepage,
+	}
+}
+
+// historyQuery selects one item table joined to its feed and project; the
+// bounds are RFC 3339 UTC strings, which sort like the times they name.
+const historyQuery = `
+	SELECT i.item_id, i.title, i.link, i.pub_date, coalesce(i.content, ''), coalesce(i.content_snippet, ''),
+		f.url, i.last_seen, coalesce(p.name, ''), coalesce(p.description, ''), coalesce(p.status, ''),
+		coalesce(p.homepage_url, ''), coalesce(p.repo, ''), coalesce(p.category, ''), coalesce(p.subcategory, '')
+	FROM %s i
+	JOIN feeds f ON f.id = i.feed_id
+	LEFT JOIN projects p ON p.id = f.project_id
+	WHERE i.pub_date >= ? AND (? = '' OR i.pub_date < ?)
+	ORDER BY i.pub_date DESC, i.id`
+
+// ReadSQLite streams every release and news item kept in the history store
+// at path, newest first within each kind. FeedTitle is not stored; ID is the
+// item's GUID (or link), and FetchedAt is the last run that saw it.
+func ReadSQLite(path string, filter Filter, fn func(Record) error) error {
+	db, err := sqlite.OpenReadOnly(path)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	since, until := "", ""
+	if !filter.Since.IsZero() {
+		since = formatTime(filter.Since)
+	}
+	if !filter.Until.IsZero() {
+		until = formatTime(filter.Until)
+	}
+	for _, t := range []struct{ kind, table string }{{KindRelease, "releases"}, {KindNews, "news"}} {
+		if filter.Kind != "" && filter.Kind != t.kind {
+			continue
+		}
+		rows, err := db.Query(fmt.Sprintf(historyQuery, t.table), since, until, until)
+		if err != nil {
+			return fmt.Errorf("query %s: %w", t.table, err)
+		}
+		err = scanRows(rows, t.kind, fn)
+		rows.Close()
+		if err != nil {
+			return fmt.Errorf("read %s: %w", t.table, err)
+		}
+	}
+	return nil
+}
+
+func scanRows(rows *sql.Rows, kind string, fn func(Record) error) error {
+	for rows.Next() {
+		rec := Record{Kind: kind}
+		var pubDate, lastSeen string
+		err := rows.Scan(&rec.ID, &rec.Title, &rec.Link, &pubDate, &rec.Content, &rec.ContentSnippet,
+			&rec.FeedURL, &lastSeen, &rec.ProjectName, &rec.ProjectDescription, &rec.ProjectStatus,
+			&rec.ProjectHomepage, &rec.ProjectRepo, &rec.ProjectCategory, &rec.ProjectSubcategory)
+		if err != nil {
+			return err
+		}
+		rec.PubDate, _ = time.Parse(time.RFC3339, pubDate)
+		rec.FetchedAt, _ = time.Parse(time.RFC3339, lastSeen)
+		if err := fn(rec); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
@@ -5,12 +5,17 @@ import (
 	"log"
 	"math/rand/v2"
 	"net/http"
+	"net/url"
 	"os"
 	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/castrojo/firehose-go/internal/metrics"
 	"github.com/castrojo/firehose-go/internal/models"
+	"github.com/castrojo/firehose-go/internal/robots"
 	"github.com/castrojo/firehose-go/internal/urlutil"
 	gofeed "github.com/mmcdole/gofeed"
 	"gopkg.in/yaml.v3"
@@ -104,6 +109,11 @@ func retryWithBackoff(fn func() error, maxAttempts int, baseDelay time.Duration,
 			return nil
 		}
 
+		// robots.txt disallows are deliberate; retrying would not change the answer.
+		if robots.IsDisallowed(lastErr) {
+			return lastErr
+		}
+
 		errorType := classifyError(lastErr)
 		// Don't retry parse errors (malformed XML/RSS) or 404/403 errors — permanent failures
 		if errorType == "parse" {
@@ -124,21 +134,81 @@ func retryWithBackoff(fn func() error, maxAttempts int, baseDelay time.Duration,
 			jitter := 0.8 + rand.Float64()*0.4
 			sleep := time.Duration(float64(backoff) * jitter)
 			log.Printf("⚠️  Retry %d/%d for %s: %v", attempt, maxAttempts, url, lastErr)
+			metrics.Default.Retry(url)
 			time.Sleep(sleep)
 		}
 	}
 	return lastErr
 }
 
+// ProbeFeed fetches and parses a feed without enriching it and returns the number
+// of items it contains. It uses the same retry policy as the main fetch path.
+func ProbeFeed(feedURL string) (int, error) {
+	var feed *gofeed.Feed
+	err := retryWithBackoff(func() error {
+		fp := gofeed.NewParser()
+		fp.Client = &http.Client{Transport: robots.Default.Transport(30 * time.Second)}
+		parsedFeed, parseErr := fp.ParseURL(feedURL)
+		if parseErr == nil {
+			feed = parsedFeed
+		}
+		return parseErr
+	}, 3, 1*time.Second, feedURL)
+	if err != nil {
+		return 0, err
+	}
+	return len(feed.Items), nil
+}
+
+// resolveURL returns ref as an absolute http(s) URL, resolving it against the
+// first base that is one. ok is false when ref is empty, malformed or cannot
+// be resolved.
+func resolveURL(ref string, bases ...string) (string, bool) {
+	u, err := url.Parse(strings.TrimSpace(ref))
+	if err != nil || ref == "" {
+		return "", false
+	}
+	if !u.IsAbs() {
+		for _, base := range bases {
+			if b, err := url.Parse(base); err == nil && isHTTP(b) {
+				u = b.ResolveReference(u)
+				break
+			}
+		}
+	}
+	if !isHTTP(u) {
+		return "", false
+	}
+	return u.String(), true
+}
+
+func isHTTP(u *url.URL) bool {
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
 // fetchSingleFeed fetches a single feed and enriches entries
 func fetchSingleFeed(source models.FeedSource, landscapeData map[string]models.LandscapeProject) ([]models.Release, models.FeedStatus) {
 	fetchedAt := time.Now().UTC()
 
 	var feed *gofeed.Feed
+	var finalURL string
 	err := retryWithBackoff(func() error {
 		// Create a new parser each attempt (parsers are not reusable after error)
 		fp := gofeed.NewParser()
-		fp.Client = &http.Client{Timeout: 30 * time.Second}
+		fp.Client = &http.Client{
+			// Sets our User-Agent, honours robots.txt Disallow and Crawl-delay, and
+			// times the request out after 30s, not counting any Crawl-delay wait.
+			Transport: robots.Default.Transport(30 * time.Second),
+			// Record where redirects end up so sync can detect renamed/transferred repos.
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) >= 10 {
+					return fmt.Errorf("stopped after 10 redirects")
+				}
+				finalURL = req.URL.String()
+				return nil
+			},
+		}
+		finalURL = ""
 		parsedFeed, parseErr := fp.ParseURL(source.URL)
 		if parseErr == nil {
 			feed = parsedFeed
@@ -150,12 +220,18 @@ func fetchSingleFeed(source models.FeedSource, landscapeData map[string]models.L
 		log.Printf("❌ Failed to fetch %s: %v", source.URL, err)
 		return nil, models.FeedStatus{
 			FeedURL:   source.URL,
+			FinalURL:  finalURL,
 			Status:    "error",
 			Error:     err.Error(),
 			ErrorType: classifyError(err),
 			FetchedAt: fetchedAt.Format(time.RFC3339),
 		}
 	}
+	if finalURL != "" {
+		log.Printf("↪️  %s redirected to %s", source.URL, finalURL)
+	}
+
+	stopEnrichment := metrics.Default.Start(metrics.StageEnrichment)
 
 	// Extract org/repo from feed URL for landscape lookup
 	orgRepo := urlutil.ExtractOrgRepo(source.URL)
@@ -201,6 +277,20 @@ func fetchSingleFeed(source models.FeedSource, landscapeData map[string]models.L
 			FetchedAt:      fetchedAt,
 		}
 
+		for _, enc := range item.Enclosures {
+			if enc == nil {
+				continue
+			}
+			// A relative or malformed enclosure URL would fail validation and
+			// drop the whole item, so resolve it or leave the enclosure out.
+			encURL, ok := resolveURL(enc.URL, item.Link, feed.Link, source.URL)
+			if !ok {
+				continue
+			}
+			length, _ := strconv.ParseInt(enc.Length, 10, 64)
+			release.Enclosures = append(release.Enclosures, models.Enclosure{URL: encURL, Type: enc.Type, Length: max(length, 0)})
+		}
+
 		// Enrich with landscape metadata if available
 		if hasLandscape {
 			release.ProjectName = landscapeProject.Name
@@ -211,14 +301,32 @@ func fetchSingleFeed(source models.FeedSource, landscapeData map[string]models.L
 
 		releases = append(releases, release)
 	}
+	stopEnrichment()
 
 	log.Printf("✅ Fetched %s: %d releases", source.URL, len(releases))
 
+	expectedProject := ""
+	if hasLandscape {
+		expectedProject = landscapeProject.Name
+	} else if source.Project != nil {
+		expectedProject = *source.Project
+	}
+	warnings := lintFeed(feed, expectedProject, orgRepo, fetchedAt)
+	if len(warnings) > 0 {
+		codes := make([]string, len(warnings))
+		for i, w := range warnings {
+			codes[i] = fmt.Sprintf("%s×%d", w.Code, w.Count)
+		}
+		log.Printf("⚠️  Lint %s: %s", source.URL, strings.Join(codes, ", "))
+	}
+
 	return releases, models.FeedStatus{
 		FeedURL:      source.URL,
+		FinalURL:     finalURL,
 		Status:       "success",
 		EntriesCount: len(releases),
 		FetchedAt:    fetchedAt.Format(time.RFC3339),
+		Warnings:     warnings,
 	}
 }
 
@@ -82,6 +82,34 @@ func TestTruncateString(t *testing.T) {
 	}
 }
 
+func TestResolveURL(t *testing.T) {
+	const itemLink = "https://example.com/blog/post/"
+	const feedLink = "https://example.com/"
+	tests := []struct {
+		name  string
+		ref   string
+		bases []string
+		want  string // "" means skipped
+	}{
+		{"absolute", "https://cdn.example.com/a.mp3", []string{itemLink, feedLink}, "https://cdn.example.com/a.mp3"},
+		{"relative to item link", "audio/a.mp3", []string{itemLink, feedLink}, "https://example.com/blog/post/audio/a.mp3"},
+		{"root-relative", "/media/a.mp3", []string{itemLink, feedLink}, "https://example.com/media/a.mp3"},
+		{"falls back to feed link", "/media/a.mp3", []string{"", feedLink}, "https://example.com/media/a.mp3"},
+		{"no base", "/media/a.mp3", []string{"", "not a url"}, ""},
+		{"empty", "", []string{itemLink}, ""},
+		{"malformed", "http://[::1", []string{itemLink}, ""},
+		{"not http", "mailto:podcast@example.com", []string{itemLink}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := resolveURL(tt.ref, tt.bases...)
+			if got != tt.want || ok != (tt.want != "") {
+				t.Errorf("resolveURL(%q) = %q, %v; want %q", tt.ref, got, ok, tt.want)
+			}
+		})
+	}
+}
+
 func TestClassifyError(t *testing.T) {
 	tests := []struct {
 		name string
@@ -412,6 +440,51 @@ func TestFetchSingleFeed(t *testing.T) {
 		}
 	})
 
+	t.Run("redirect records final URL", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/old-org/repo/releases.atom" {
+				http.Redirect(w, r, "/new-org/repo/releases.atom", http.StatusMovedPermanently)
+				return
+			}
+			w.Header().Set("Content-Type", "application/rss+xml")
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprint(w, validRSS)
+		}))
+		defer server.Close()
+
+		source := models.FeedSource{
+			URL:      server.URL + "/old-org/repo/releases.atom",
+			Category: "sandbox",
+		}
+
+		_, status := fetchSingleFeed(source, make(map[string]models.LandscapeProject))
+
+		if status.Status != "success" {
+			t.Fatalf("expected status 'success', got '%s'", status.Status)
+		}
+		if want := server.URL + "/new-org/repo/releases.atom"; status.FinalURL != want {
+			t.Errorf("expected final URL '%s', got '%s'", want, status.FinalURL)
+		}
+		if status.FeedURL != source.URL {
+			t.Errorf("expected feed URL '%s', got '%s'", source.URL, status.FeedURL)
+		}
+	})
+
+	t.Run("no redirect leaves final URL empty", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/rss+xml")
+			w.WriteHeader(http.StatusOK)
+			fmt.Fprint(w, validRSS)
+		}))
+		defer server.Close()
+
+		_, status := fetchSingleFeed(models.FeedSource{URL: server.URL, Category: "sandbox"}, make(map[string]models.LandscapeProject))
+
+		if status.FinalURL != "" {
+			t.Errorf("expected empty final URL, got '%s'", status.FinalURL)
+		}
+	})
+
 	t.Run("server returns 500 error", func(t *testing.T) {
 		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusInternalServerError)
@@ -0,0 +1,138 @@
+package feeds
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/castrojo/firehose-go/internal/models"
+	gofeed "github.com/mmcdole/gofeed"
+)
+
+// Lint warning codes recorded in FeedStatus.Warnings.
+const (
+	WarnMissingDate   = "missing-date"
+	WarnDuplicateGUID = "duplicate-guid"
+	WarnFutureDate    = "future-date"
+	WarnEmptyTitle    = "empty-title"
+	WarnInsecureLink  = "insecure-link"
+	WarnTitleMismatch = "title-mismatch"
+	WarnHTMLTitle     = "html-title"
+)
+
+// futureDateSlack tolerates publishers whose clocks or time zones are slightly off.
+const futureDateSlack = 24 * time.Hour
+
+var (
+	htmlTagPattern = regexp.MustCompile(`</?[a-zA-Z][a-zA-Z0-9]*(\s[^>]*)?/?>`)
+	nonAlnum       = regexp.MustCompile(`[^a-z0-9]+`)
+	parenthesized  = regexp.MustCompile(`\(([^)]+)\)`)
+)
+
+// lintFeed checks a parsed feed for problems that do not stop it from loading
+// but degrade what readers see. project is the expected project name; when
+// empty the feed title is not checked. orgRepo is the feed's GitHub org/repo,
+// if any, whose names the title may use instead. Each code is reported once
+// with the number of offending items and the first one as an example.
+func lintFeed(feed *gofeed.Feed, project, orgRepo string, now time.Time) []models.FeedWarning {
+	found := make(map[string]*models.FeedWarning)
+	flag := func(code, example string) {
+		w, ok := found[code]
+		if !ok {
+			w = &models.FeedWarning{Code: code, Example: truncateString(example, 200)}
+			found[code] = w
+		}
+		w.Count++
+	}
+
+	seenGUIDs := make(map[string]bool, len(feed.Items))
+	for _, item := range feed.Items {
+		label := item.Title
+		if strings.TrimSpace(label) == "" {
+			label = item.Link
+		}
+
+		if item.PublishedParsed == nil {
+			flag(WarnMissingDate, label)
+		} else if item.PublishedParsed.After(now.Add(futureDateSlack)) {
+			flag(WarnFutureDate, fmt.Sprintf("%s (%s)", label, item.PublishedParsed.Format(time.RFC3339)))
+		}
+		if item.GUID != "" {
+			if seenGUIDs[item.GUID] {
+				flag(WarnDuplicateGUID, item.GUID)
+			}
+			seenGUIDs[item.GUID] = true
+		}
+		if strings.TrimSpace(item.Title) == "" {
+			flag(WarnEmptyTitle, item.Link)
+		} else if htmlTagPattern.MatchString(item.Title) {
+			flag(WarnHTMLTitle, item.Title)
+		}
+		if u, err := url.Parse(item.Link); err == nil && u.Scheme == "http" {
+			flag(WarnInsecureLink, item.Link)
+		}
+	}
+
+	if project != "" && !titleMentions(feed.Title, project, orgRepo) {
+		w := &models.FeedWarning{Code: WarnTitleMismatch, Count: 1, Example: feed.Title}
+		found[WarnTitleMismatch] = w
+	}
+
+	warnings := make([]models.FeedWarning, 0, len(found))
+	for _, w := range found {
+		warnings = append(warnings, *w)
+	}
+	sort.Slice(warnings, func(i, j int) bool { return warnings[i].Code < warnings[j].Code })
+	return warnings
+}
+
+// titleMentions reports whether a feed title refers to the project, comparing
+// alphanumerics only. The full name, a parenthesized short name such as the
+// "OPA" in "Open Policy Agent (OPA)", or the name's first word all count, as
+// do the org and repo of orgRepo, since GitHub titles release feeds "Release
+// notes from <repo>".
+func titleMentions(title, project, orgRepo string) bool {
+	normTitle := normalize(title)
+	if normTitle == "" {
+		return false
+	}
+	names := []string{parenthesized.ReplaceAllString(project, "")}
+	if m := parenthesized.FindStringSubmatch(project); m != nil {
+		names = append(names, m[1])
+	}
+	if fields := strings.Fields(project); len(fields) > 1 {
+		names = append(names, fields[0])
+	}
+	if orgRepo != "" {
+		names = append(names, strings.Split(orgRepo, "/")...)
+	}
+	for _, name := range names {
+		if n := normalize(name); len(n) >= 3 && strings.Contains(normTitle, n) {
+			return true
+		}
+	}
+	return false
+}
+
+func normalize(s string) string {
+	return nonAlnum.ReplaceAllString(strings.ToLower(s), "")
+}
+
+// SummarizeWarnings counts the feeds with lint warnings and, per warning code,
+// the number of feeds affected.
+func SummarizeWarnings(statuses []models.FeedStatus) (feedsWithWarnings int, byCode map[string]int) {
+	byCode = make(map[string]int)
+	for _, status := range statuses {
+		if len(status.Warnings) == 0 {
+			continue
+		}
+		feedsWithWarnings++
+		for _, w := range status.Warnings {
+			byCode[w.Code]++
+		}
+	}
+	return feedsWithWarnings, byCode
+}
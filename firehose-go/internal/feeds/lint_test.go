@@ -0,0 +1,132 @@
+package feeds
+
+import (
+	"testing"
+	"time"
+
+	"github.com/castrojo/firehose-go/internal/models"
+	gofeed "github.com/mmcdole/gofeed"
+)
+
+func TestLintFeed(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	past := now.Add(-48 * time.Hour)
+	future := now.Add(72 * time.Hour)
+	almostNow := now.Add(2 * time.Hour)
+
+	item := func(title, link, guid string, date *time.Time) *gofeed.Item {
+		return &gofeed.Item{Title: title, Link: link, GUID: guid, PublishedParsed: date}
+	}
+
+	tests := []struct {
+		name    string
+		feed    *gofeed.Feed
+		project string
+		orgRepo string
+		want    map[string]int // code → count
+	}{
+		{
+			name: "clean feed",
+			feed: &gofeed.Feed{Title: "Release notes from argo-cd", Items: []*gofeed.Item{
+				item("v2.10.0", "https://github.com/argoproj/argo-cd/releases/tag/v2.10.0", "tag:1", &past),
+				item("v2.9.0", "https://github.com/argoproj/argo-cd/releases/tag/v2.9.0", "tag:2", &almostNow),
+			}},
+			project: "Argo",
+			want:    map[string]int{},
+		},
+		{
+			name: "item problems",
+			feed: &gofeed.Feed{Title: "Example Blog", Items: []*gofeed.Item{
+				item("No date", "https://example.io/1", "1", nil),
+				item("Also no date", "https://example.io/2", "2", nil),
+				item("Duplicate", "https://example.io/3", "1", &past),
+				item("From the future", "https://example.io/4", "4", &future),
+				item("  ", "https://example.io/5", "5", &past),
+				item("Plain link", "http://example.io/6", "6", &past),
+				item("Release <b>v1.0</b>", "https://example.io/7", "7", &past),
+			}},
+			project: "Example",
+			want: map[string]int{
+				WarnMissingDate:   2,
+				WarnDuplicateGUID: 1,
+				WarnFutureDate:    1,
+				WarnEmptyTitle:    1,
+				WarnInsecureLink:  1,
+				WarnHTMLTitle:     1,
+			},
+		},
+		{
+			name:    "title does not mention project",
+			feed:    &gofeed.Feed{Title: "Company Engineering Blog"},
+			project: "Example",
+			want:    map[string]int{WarnTitleMismatch: 1},
+		},
+		{
+			name:    "parenthesized short name matches",
+			feed:    &gofeed.Feed{Title: "Release notes from opa"},
+			project: "Open Policy Agent (OPA)",
+			want:    map[string]int{},
+		},
+		{
+			name:    "repo name matches",
+			feed:    &gofeed.Feed{Title: "Release notes from opa"},
+			project: "Open Policy Agent",
+			orgRepo: "open-policy-agent/opa",
+			want:    map[string]int{},
+		},
+		{
+			name:    "repo name of another project does not match",
+			feed:    &gofeed.Feed{Title: "Release notes from opa"},
+			project: "Open Policy Agent",
+			orgRepo: "open-policy-agent/gatekeeper",
+			want:    map[string]int{WarnTitleMismatch: 1},
+		},
+		{
+			name:    "no project skips title check",
+			feed:    &gofeed.Feed{Title: "Anything"},
+			project: "",
+			want:    map[string]int{},
+		},
+		{
+			name:    "comparison in title is not HTML",
+			feed:    &gofeed.Feed{Title: "Example", Items: []*gofeed.Item{item("Fix a < b > c", "https://example.io/1", "1", &past)}},
+			project: "Example",
+			want:    map[string]int{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := make(map[string]int)
+			for _, w := range lintFeed(tt.feed, tt.project, tt.orgRepo, now) {
+				got[w.Code] = w.Count
+				if w.Example == "" && w.Code != WarnTitleMismatch {
+					t.Errorf("%s warning has no example", w.Code)
+				}
+			}
+			if len(got) != len(tt.want) {
+				t.Errorf("lintFeed() = %v, want %v", got, tt.want)
+			}
+			for code, count := range tt.want {
+				if got[code] != count {
+					t.Errorf("%s count = %d, want %d", code, got[code], count)
+				}
+			}
+		})
+	}
+}
+
+func TestSummarizeWarnings(t *testing.T) {
+	statuses := []models.FeedStatus{
+		{FeedURL: "https://a.example/feed", Warnings: []models.FeedWarning{{Code: WarnMissingDate, Count: 3}, {Code: WarnInsecureLink, Count: 1}}},
+		{FeedURL: "https://b.example/feed", Warnings: []models.FeedWarning{{Code: WarnMissingDate, Count: 1}}},
+		{FeedURL: "https://c.example/feed"},
+	}
+	feedsWithWarnings, byCode := SummarizeWarnings(statuses)
+	if feedsWithWarnings != 2 {
+		t.Errorf("feedsWithWarnings = %d, want 2", feedsWithWarnings)
+	}
+	if byCode[WarnMissingDate] != 2 || byCode[WarnInsecureLink] != 1 || len(byCode) != 2 {
+		t.Errorf("byCode = %v", byCode)
+	}
+}
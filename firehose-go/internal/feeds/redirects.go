@@ -0,0 +1,78 @@
+package feeds
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/castrojo/firehose-go/internal/models"
+	"github.com/castrojo/firehose-go/internal/robots"
+)
+
+// ProbeRedirects checks where each feed URL redirects to without downloading
+// or parsing the feeds, for callers that only need FeedStatus.FinalURL. Each
+// feed gets one HEAD request (GET when the server rejects HEAD) whose body is
+// never read. Status is "success" for a 2xx response and "error" otherwise.
+func ProbeRedirects(sources []models.FeedSource) []models.FeedStatus {
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		statuses []models.FeedStatus
+	)
+	sem := make(chan struct{}, 20)
+	for _, source := range sources {
+		wg.Add(1)
+		go func(src models.FeedSource) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			status := probeRedirect(src.URL)
+			mu.Lock()
+			statuses = append(statuses, status)
+			mu.Unlock()
+		}(source)
+	}
+	wg.Wait()
+	return statuses
+}
+
+func probeRedirect(feedURL string) models.FeedStatus {
+	start := time.Now()
+	status := models.FeedStatus{FeedURL: feedURL, FetchedAt: start.UTC().Format(time.RFC3339)}
+
+	var finalURL string
+	client := &http.Client{
+		Transport: robots.Default.Transport(30 * time.Second),
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 10 {
+				return fmt.Errorf("stopped after 10 redirects")
+			}
+			finalURL = req.URL.String()
+			return nil
+		},
+	}
+	resp, err := client.Head(feedURL)
+	if err == nil && resp.StatusCode == http.StatusMethodNotAllowed {
+		resp.Body.Close()
+		finalURL = ""
+		resp, err = client.Get(feedURL)
+	}
+	status.Duration = time.Since(start).String()
+	status.FinalURL = finalURL
+	if err != nil {
+		status.Status = "error"
+		status.Error = err.Error()
+		status.ErrorType = classifyError(err)
+		return status
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		status.Status = "error"
+		status.Error = fmt.Sprintf("HTTP %d", resp.StatusCode)
+		return status
+	}
+	status.Status = "success"
+	return status
+}
@@ -0,0 +1,68 @@
+package feeds
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync/atomic"
+	"testing"
+
+	"github.com/castrojo/firehose-go/internal/models"
+)
+
+func TestProbeRedirects(t *testing.T) {
+	var bodies atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/old-org/repo/releases.atom":
+			http.Redirect(w, r, "/new-org/repo/releases.atom", http.StatusMovedPermanently)
+		case "/no-head/releases.atom":
+			if r.Method == http.MethodHead {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			http.Redirect(w, r, "/moved/releases.atom", http.StatusFound)
+		case "/robots.txt", "/missing/releases.atom":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			if r.Method != http.MethodHead {
+				bodies.Add(1)
+			}
+			fmt.Fprint(w, "<feed/>")
+		}
+	}))
+	defer server.Close()
+
+	statuses := ProbeRedirects([]models.FeedSource{
+		{URL: server.URL + "/old-org/repo/releases.atom"},
+		{URL: server.URL + "/no-head/releases.atom"},
+		{URL: server.URL + "/same/releases.atom"},
+		{URL: server.URL + "/missing/releases.atom"},
+	})
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].FeedURL < statuses[j].FeedURL })
+
+	want := []struct{ path, finalPath, status string }{
+		{"/missing/releases.atom", "", "error"},
+		{"/no-head/releases.atom", "/moved/releases.atom", "success"},
+		{"/old-org/repo/releases.atom", "/new-org/repo/releases.atom", "success"},
+		{"/same/releases.atom", "", "success"},
+	}
+	if len(statuses) != len(want) {
+		t.Fatalf("ProbeRedirects() returned %d statuses, want %d", len(statuses), len(want))
+	}
+	for i, w := range want {
+		s := statuses[i]
+		finalURL := ""
+		if w.finalPath != "" {
+			finalURL = server.URL + w.finalPath
+		}
+		if s.FeedURL != server.URL+w.path || s.FinalURL != finalURL || s.Status != w.status {
+			t.Errorf("status %d = %+v, want %s → %q (%s)", i, s, w.path, finalURL, w.status)
+		}
+	}
+	// Only the server rejecting HEAD is sent a GET.
+	if n := bodies.Load(); n != 1 {
+		t.Errorf("%d feed bodies requested, want 1", n)
+	}
+}
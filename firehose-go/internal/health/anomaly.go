@@ -0,0 +1,248 @@
+package health
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/castrojo/firehose-go/internal/models"
+)
+
+// Anomaly rule names recorded in Anomaly.Rule.
+const (
+	RuleCountCollapse   = "count-collapse"
+	RuleBurst           = "burst"
+	RuleAllItemsChanged = "all-items-changed"
+)
+
+// AnomalyRules tunes anomaly detection. Zero fields take the defaults below.
+type AnomalyRules struct {
+	// BurstItems is the number of new items within BurstWindow that counts as a
+	// burst. By default it is sized per feed: half the feed's usual item count,
+	// between minBaselineItems and 10.
+	BurstItems  int
+	BurstWindow time.Duration // publishing window for bursts (default 1h)
+	// BurstFactor raises the burst threshold for feeds that normally publish a
+	// lot: at least BurstFactor × the feed's average new items per run (default 3).
+	BurstFactor float64
+	// CollapseRatio flags a feed whose item count falls below this fraction of
+	// its average (default 0.2).
+	CollapseRatio float64
+	// MinBaselineRuns is the number of successful runs needed before a feed's
+	// averages are trusted (default 3).
+	MinBaselineRuns int
+}
+
+func (r AnomalyRules) withDefaults() AnomalyRules {
+	if r.BurstWindow <= 0 {
+		r.BurstWindow = time.Hour
+	}
+	if r.BurstFactor <= 0 {
+		r.BurstFactor = 3
+	}
+	if r.CollapseRatio <= 0 {
+		r.CollapseRatio = 0.2
+	}
+	if r.MinBaselineRuns <= 0 {
+		r.MinBaselineRuns = 3
+	}
+	return r
+}
+
+// burstItems returns the burst threshold for a feed before BurstFactor is
+// applied. A feed that only ever lists 10 items can never show 10 new ones in
+// an hour, so the default is half its usual item count, capped at
+// defaultBurstItems and at least minBaselineItems.
+func (r AnomalyRules) burstItems(rec *Record) int {
+	if r.BurstItems > 0 {
+		return r.BurstItems
+	}
+	return min(defaultBurstItems, max(minBaselineItems, int(math.Ceil(0.5*rec.AvgEntries))))
+}
+
+// minBaselineItems keeps tiny feeds, where a drop from 2 items to 0 is normal
+// churn, out of the collapse and all-items-changed rules.
+const minBaselineItems = 5
+
+// defaultBurstItems caps the default per-feed burst threshold.
+const defaultBurstItems = 10
+
+// Anomaly is one feed whose results this run deviate from its history.
+type Anomaly struct {
+	FeedURL string `json:"feedUrl"`
+	Rule    string `json:"rule"`
+	Detail  string `json:"detail"`
+	Held    int    `json:"held,omitempty"` // burst items withheld from output
+
+	itemIDs []string // hashed IDs of the burst items
+}
+
+// Detect compares this run's results with each feed's history. It must run
+// before Update, which replaces the history with this run.
+func (s *State) Detect(statuses []models.FeedStatus, items []models.Release, rules AnomalyRules) []Anomaly {
+	rules = rules.withDefaults()
+	byFeed := groupByFeed(items)
+
+	var anomalies []Anomaly
+	for _, status := range statuses {
+		rec, ok := s.Feeds[status.FeedURL]
+		if !ok || status.Status != "success" || rec.Successes < rules.MinBaselineRuns {
+			continue
+		}
+		feedItems := byFeed[status.FeedURL]
+
+		if rec.AvgEntries >= minBaselineItems && float64(status.EntriesCount) < rules.CollapseRatio*rec.AvgEntries {
+			anomalies = append(anomalies, Anomaly{
+				FeedURL: status.FeedURL,
+				Rule:    RuleCountCollapse,
+				Detail:  fmt.Sprintf("%d items, usually %.1f", status.EntriesCount, rec.AvgEntries),
+			})
+		}
+
+		if len(rec.ItemIDs) == 0 {
+			continue
+		}
+		fresh := newItems(rec.ItemIDs, feedItems)
+		if len(fresh) == len(feedItems) && len(feedItems) >= minBaselineItems && len(rec.ItemIDs) >= minBaselineItems {
+			anomalies = append(anomalies, Anomaly{
+				FeedURL: status.FeedURL,
+				Rule:    RuleAllItemsChanged,
+				Detail:  fmt.Sprintf("none of %d items were in the previous run (GUID or link scheme changed?)", len(feedItems)),
+			})
+		}
+
+		threshold := max(rules.burstItems(rec), int(math.Ceil(rules.BurstFactor*rec.AvgNewItems)))
+		if burst := largestBurst(fresh, rules.BurstWindow); len(burst) >= threshold {
+			anomalies = append(anomalies, Anomaly{
+				FeedURL: status.FeedURL,
+				Rule:    RuleBurst,
+				Detail: fmt.Sprintf("%d new items published within %s (threshold %d, usually %.1f new per run)",
+					len(burst), rules.BurstWindow, threshold, rec.AvgNewItems),
+				itemIDs: itemIDs(burst),
+			})
+		}
+	}
+
+	sort.Slice(anomalies, func(i, j int) bool {
+		if anomalies[i].FeedURL != anomalies[j].FeedURL {
+			return anomalies[i].FeedURL < anomalies[j].FeedURL
+		}
+		return anomalies[i].Rule < anomalies[j].Rule
+	})
+	return anomalies
+}
+
+// Hold withholds burst items from items until a reviewer releases them.
+// It must run after Update so held IDs are checked against this run's items.
+// Burst items found this run join the feed's held set, stamped with now; the
+// set persists across runs, so items stay out of the output after the burst
+// has passed. reviewed maps a feed URL to the time a reviewer checked it:
+// items held at or before then are released, while later bursts are held
+// again. It returns the items to publish and the number withheld.
+func (s *State) Hold(anomalies []Anomaly, items []models.Release, reviewed map[string]time.Time, now time.Time) ([]models.Release, int) {
+	for i := range anomalies {
+		a := &anomalies[i]
+		rec, ok := s.Feeds[a.FeedURL]
+		if !ok || a.Rule != RuleBurst {
+			continue
+		}
+		if rec.Held == nil {
+			rec.Held = make(map[string]time.Time, len(a.itemIDs))
+		}
+		for _, id := range a.itemIDs {
+			if _, ok := rec.Held[id]; !ok {
+				rec.Held[id] = now.UTC()
+				a.Held++
+			}
+		}
+	}
+
+	for feedURL, rec := range s.Feeds {
+		// Release reviewed items and forget held items that have dropped out of the feed.
+		current := toSet(rec.ItemIDs)
+		at, ok := reviewed[feedURL]
+		for id, heldAt := range rec.Held {
+			if !current[id] || (ok && !heldAt.After(at)) {
+				delete(rec.Held, id)
+			}
+		}
+		if len(rec.Held) == 0 {
+			rec.Held = nil
+		}
+	}
+
+	kept := make([]models.Release, 0, len(items))
+	withheld := 0
+	for _, item := range items {
+		if rec, ok := s.Feeds[item.FeedURL]; ok && !rec.Held[itemID(item)].IsZero() {
+			withheld++
+			continue
+		}
+		kept = append(kept, item)
+	}
+	return kept, withheld
+}
+
+// largestBurst returns the most new items published within any window.
+func largestBurst(items []models.Release, window time.Duration) []models.Release {
+	sorted := append([]models.Release(nil), items...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].PubDate.Before(sorted[j].PubDate) })
+
+	var bestStart, bestEnd int
+	start := 0
+	for end := range sorted {
+		for sorted[end].PubDate.Sub(sorted[start].PubDate) > window {
+			start++
+		}
+		if end+1-start > bestEnd-bestStart {
+			bestStart, bestEnd = start, end+1
+		}
+	}
+	return sorted[bestStart:bestEnd]
+}
+
+// newItems returns the items whose IDs are not among previous.
+func newItems(previous []string, items []models.Release) []models.Release {
+	seen := toSet(previous)
+	var fresh []models.Release
+	for _, item := range items {
+		if !seen[itemID(item)] {
+			fresh = append(fresh, item)
+		}
+	}
+	return fresh
+}
+
+func groupByFeed(items []models.Release) map[string][]models.Release {
+	byFeed := make(map[string][]models.Release)
+	for _, item := range items {
+		byFeed[item.FeedURL] = append(byFeed[item.FeedURL], item)
+	}
+	return byFeed
+}
+
+// itemID hashes an item's ID to keep the state file small.
+func itemID(item models.Release) string {
+	h := fnv.New64a()
+	h.Write([]byte(item.ID))
+	return strconv.FormatUint(h.Sum64(), 36)
+}
+
+func itemIDs(items []models.Release) []string {
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = itemID(item)
+	}
+	return ids
+}
+
+func toSet(ids []string) map[string]bool {
+	set := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	return set
+}
@@ -0,0 +1,160 @@
+package health
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/castrojo/firehose-go/internal/models"
+)
+
+// releases returns n items for testFeed with IDs prefix-0..n-1, published step apart before end.
+func releases(prefix string, n int, end time.Time, step time.Duration) []models.Release {
+	items := make([]models.Release, n)
+	for i := range items {
+		items[i] = models.Release{
+			ID:      fmt.Sprintf("%s-%d", prefix, i),
+			FeedURL: testFeed,
+			PubDate: end.Add(-time.Duration(i) * step),
+		}
+	}
+	return items
+}
+
+// baseline runs three quiet successful fetches: 10 items, one new per day.
+func baseline(t *testing.T, start time.Time) (*State, []models.Release) {
+	t.Helper()
+	state := NewState()
+	day := 24 * time.Hour
+	items := releases("v", 10, start, day)
+	for run := 0; run < 3; run++ {
+		now := start.Add(time.Duration(run) * day)
+		items = append([]models.Release{{ID: fmt.Sprintf("daily-%d", run), FeedURL: testFeed, PubDate: now}}, items[:9]...)
+		if got := state.Detect(success(len(items)), items, AnomalyRules{}); len(got) != 0 {
+			t.Fatalf("baseline run %d: unexpected anomalies %+v", run, got)
+		}
+		state.Update(KindRelease, success(len(items)), items, now)
+	}
+	return state, items
+}
+
+func TestDetect(t *testing.T) {
+	start := time.Date(2026, 3, 1, 6, 0, 0, 0, time.UTC)
+	now := start.Add(3 * 24 * time.Hour)
+
+	t.Run("count collapse", func(t *testing.T) {
+		state, _ := baseline(t, start)
+		got := state.Detect(success(0), nil, AnomalyRules{})
+		if len(got) != 1 || got[0].Rule != RuleCountCollapse {
+			t.Errorf("Detect() = %+v, want count-collapse", got)
+		}
+	})
+
+	t.Run("burst", func(t *testing.T) {
+		state, previous := baseline(t, start)
+		burst := releases("bot", 12, now, time.Minute)
+		items := append(burst, previous[:3]...)
+		got := state.Detect(success(len(items)), items, AnomalyRules{})
+		if len(got) != 1 || got[0].Rule != RuleBurst {
+			t.Fatalf("Detect() = %+v, want burst", got)
+		}
+		if len(got[0].itemIDs) != 12 {
+			t.Errorf("burst holds %d items, want 12", len(got[0].itemIDs))
+		}
+	})
+
+	t.Run("spread out new items are not a burst", func(t *testing.T) {
+		state, previous := baseline(t, start)
+		items := append(releases("weekly", 12, now, 2*time.Hour), previous[:3]...)
+		for _, a := range state.Detect(success(len(items)), items, AnomalyRules{}) {
+			if a.Rule == RuleBurst {
+				t.Errorf("unexpected burst: %+v", a)
+			}
+		}
+	})
+
+	t.Run("custom burst threshold", func(t *testing.T) {
+		state, previous := baseline(t, start)
+		items := append(releases("bot", 4, now, time.Minute), previous[:6]...)
+		got := state.Detect(success(len(items)), items, AnomalyRules{BurstItems: 4, BurstWindow: 10 * time.Minute})
+		if len(got) != 1 || got[0].Rule != RuleBurst {
+			t.Errorf("Detect() = %+v, want burst", got)
+		}
+	})
+
+	t.Run("all items changed", func(t *testing.T) {
+		state, _ := baseline(t, start)
+		items := releases("renamed", 10, now, 24*time.Hour)
+		got := state.Detect(success(len(items)), items, AnomalyRules{})
+		if len(got) != 1 || got[0].Rule != RuleAllItemsChanged {
+			t.Errorf("Detect() = %+v, want all-items-changed", got)
+		}
+	})
+
+	t.Run("no baseline yet", func(t *testing.T) {
+		state := NewState()
+		state.Update(KindRelease, success(10), releases("v", 10, start, time.Hour), start)
+		if got := state.Detect(success(0), nil, AnomalyRules{}); len(got) != 0 {
+			t.Errorf("Detect() with one run of history = %+v, want none", got)
+		}
+	})
+}
+
+func TestHold(t *testing.T) {
+	start := time.Date(2026, 3, 1, 6, 0, 0, 0, time.UTC)
+	now := start.Add(3 * 24 * time.Hour)
+	state, previous := baseline(t, start)
+
+	burst := releases("bot", 12, now, time.Minute)
+	items := append(burst, previous[:3]...)
+	anomalies := state.Detect(success(len(items)), items, AnomalyRules{})
+	state.Update(KindRelease, success(len(items)), items, now)
+
+	kept, held := state.Hold(anomalies, items, nil, now)
+	if held != 12 || len(kept) != 3 || anomalies[0].Held != 12 {
+		t.Fatalf("Hold() kept %d, held %d (anomaly held %d); want 3 kept, 12 held", len(kept), held, anomalies[0].Held)
+	}
+
+	// The next day the burst is no longer new, but its items stay held.
+	next := now.Add(24 * time.Hour)
+	anomalies = state.Detect(success(len(items)), items, AnomalyRules{})
+	state.Update(KindRelease, success(len(items)), items, next)
+	if _, held := state.Hold(anomalies, items, nil, next); held != 12 {
+		t.Errorf("second run held %d items, want 12", held)
+	}
+
+	// A reviewer releases the burst: held items are published.
+	reviewed := map[string]time.Time{testFeed: next}
+	kept, held = state.Hold(nil, items, reviewed, next)
+	if held != 0 || len(kept) != len(items) || len(state.Feeds[testFeed].Held) != 0 {
+		t.Errorf("after review: kept %d, held %d, held IDs %d", len(kept), held, len(state.Feeds[testFeed].Held))
+	}
+
+	// The review does not exempt the feed: a later burst is held again.
+	later := next.Add(24 * time.Hour)
+	burst = releases("bot2", 12, later, time.Minute)
+	items = append(burst, items[:3]...)
+	anomalies = state.Detect(success(len(items)), items, AnomalyRules{})
+	state.Update(KindRelease, success(len(items)), items, later)
+	if kept, held := state.Hold(anomalies, items, reviewed, later); held != 12 || len(kept) != 3 {
+		t.Errorf("burst after review: kept %d, held %d; want 3 kept, 12 held", len(kept), held)
+	}
+}
+
+func TestBurstThreshold(t *testing.T) {
+	tests := []struct {
+		rules      AnomalyRules
+		avgEntries float64
+		want       int
+	}{
+		{AnomalyRules{}, 10, 5},  // GitHub release feeds list 10 items
+		{AnomalyRules{}, 50, 10}, // Capped
+		{AnomalyRules{}, 2, 5},   // Tiny feeds keep a floor
+		{AnomalyRules{BurstItems: 3}, 50, 3},
+	}
+	for _, tt := range tests {
+		if got := tt.rules.burstItems(&Record{AvgEntries: tt.avgEntries}); got != tt.want {
+			t.Errorf("burstItems(%+v, avg %.0f) = %d, want %d", tt.rules, tt.avgEntries, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,243 @@
+// Package health keeps a per-feed health record across pipeline runs.
+//
+// Each run's FeedStatus results are folded into a persisted State: last success,
+// consecutive failures, newest item date, average entry count and recent errors.
+// Feeds that keep failing are quarantined: they are skipped until a periodic
+// re-probe succeeds.
+package health
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/castrojo/firehose-go/internal/atomicfile"
+	"github.com/castrojo/firehose-go/internal/models"
+)
+
+// Feed kinds recorded in Record.Kind.
+const (
+	KindRelease = "release"
+	KindBlog    = "blog"
+)
+
+const (
+	// QuarantineAfter is the number of consecutive failures that quarantines a feed.
+	QuarantineAfter = 5
+	// ReprobeInterval is how long a quarantined feed is skipped before it is tried again.
+	ReprobeInterval = 7 * 24 * time.Hour
+	// maxErrors bounds the error history kept per feed.
+	maxErrors = 10
+	// avgWindow bounds the number of runs the entry average is taken over,
+	// so it follows a feed whose volume changes.
+	avgWindow = 30
+)
+
+// State is the persisted health of every configured feed.
+type State struct {
+	Feeds map[string]*Record `json:"feeds"` // keyed by feed URL
+}
+
+// Record is the health history of one feed.
+type Record struct {
+	Kind                string               `json:"kind"`
+	LastAttempt         time.Time            `json:"lastAttempt"`
+	LastSuccess         time.Time            `json:"lastSuccess,omitzero"`
+	ConsecutiveFailures int                  `json:"consecutiveFailures"`
+	Successes           int                  `json:"successes"`
+	LastItemDate        time.Time            `json:"lastItemDate,omitzero"`
+	AvgEntries          float64              `json:"avgEntries"`
+	Errors              []ErrorEntry         `json:"errors,omitempty"` // oldest first
+	QuarantinedAt       time.Time            `json:"quarantinedAt,omitzero"`
+	NextProbe           time.Time            `json:"nextProbe,omitzero"`
+	ItemIDs             []string             `json:"itemIds,omitempty"` // hashed IDs from the last successful fetch
+	AvgNewItems         float64              `json:"avgNewItems"`       // items per run not seen in the previous run
+	Held                map[string]time.Time `json:"held,omitempty"`    // hashed IDs of burst items held for review → when first held
+}
+
+// ErrorEntry is one failed fetch.
+type ErrorEntry struct {
+	At      time.Time `json:"at"`
+	Type    string    `json:"type,omitempty"`
+	Message string    `json:"message"`
+}
+
+// Quarantined reports whether the feed is currently quarantined.
+func (r *Record) Quarantined() bool {
+	return !r.QuarantinedAt.IsZero()
+}
+
+// NewState returns an empty state.
+func NewState() *State {
+	return &State{Feeds: make(map[string]*Record)}
+}
+
+// Load reads the state file. A missing file yields an empty state.
+func Load(path string) (*State, error) {
+	state := NewState()
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read health state: %w", err)
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("parse health state: %w", err)
+	}
+	if state.Feeds == nil {
+		state.Feeds = make(map[string]*Record)
+	}
+	return state, nil
+}
+
+// Save writes the state file, creating its directory if needed.
+func (s *State) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal health state: %w", err)
+	}
+	return atomicfile.WriteFile(path, append(data, '\n'), 0644, atomicfile.VerifyJSON[State]())
+}
+
+// Skip reports whether feedURL is quarantined and not yet due for a re-probe.
+func (s *State) Skip(feedURL string, now time.Time) bool {
+	rec, ok := s.Feeds[feedURL]
+	return ok && rec.Quarantined() && now.Before(rec.NextProbe)
+}
+
+// Active returns the sources to fetch this run and how many were skipped
+// because they are quarantined and not yet due for a re-probe. Feeds in always
+// are fetched every run even while quarantined: a must_succeed feed that is
+// not fetched fails the run, so skipping it would fail every run until its
+// re-probe.
+func Active[T any](s *State, sources []T, feedURL func(T) string, always map[string]bool, now time.Time) ([]T, int) {
+	active := make([]T, 0, len(sources))
+	for _, src := range sources {
+		if url := feedURL(src); always[url] || !s.Skip(url, now) {
+			active = append(active, src)
+		}
+	}
+	return active, len(sources) - len(active)
+}
+
+// Update folds one run's fetch results into the state. items are the releases
+// or news items fetched in that run; their newest PubDate per feed becomes the
+// feed's last item date and their IDs the baseline for anomaly detection.
+// It returns the feeds quarantined by this run.
+func (s *State) Update(kind string, statuses []models.FeedStatus, items []models.Release, now time.Time) []string {
+	byFeed := groupByFeed(items)
+
+	now = now.UTC()
+	var quarantined []string
+	for _, status := range statuses {
+		rec, ok := s.Feeds[status.FeedURL]
+		if !ok {
+			rec = &Record{}
+			s.Feeds[status.FeedURL] = rec
+		}
+		rec.Kind = kind
+		rec.LastAttempt = now
+
+		if status.Status == "success" {
+			rec.recordSuccess(status.EntriesCount, byFeed[status.FeedURL], now)
+			continue
+		}
+		wasQuarantined := rec.Quarantined()
+		rec.recordFailure(status, now)
+		if rec.Quarantined() && !wasQuarantined {
+			quarantined = append(quarantined, status.FeedURL)
+		}
+	}
+	sort.Strings(quarantined)
+	return quarantined
+}
+
+func (r *Record) recordSuccess(entries int, items []models.Release, now time.Time) {
+	n := float64(min(r.Successes, avgWindow-1))
+	r.AvgEntries = (r.AvgEntries*n + float64(entries)) / (n + 1)
+
+	// The new-item baseline needs a previous fetch to compare against.
+	ids := itemIDs(items)
+	if len(r.ItemIDs) > 0 {
+		fresh := float64(len(newItems(r.ItemIDs, items)))
+		m := float64(min(r.Successes-1, avgWindow-1))
+		r.AvgNewItems = (r.AvgNewItems*m + fresh) / (m + 1)
+	}
+	r.ItemIDs = ids
+
+	r.Successes++
+	r.LastSuccess = now
+	r.ConsecutiveFailures = 0
+	for _, item := range items {
+		if item.PubDate.After(r.LastItemDate) {
+			r.LastItemDate = item.PubDate.UTC()
+		}
+	}
+	r.QuarantinedAt = time.Time{}
+	r.NextProbe = time.Time{}
+}
+
+func (r *Record) recordFailure(status models.FeedStatus, now time.Time) {
+	r.ConsecutiveFailures++
+	r.Errors = append(r.Errors, ErrorEntry{At: now, Type: status.ErrorType, Message: status.Error})
+	if len(r.Errors) > maxErrors {
+		r.Errors = r.Errors[len(r.Errors)-maxErrors:]
+	}
+	if r.ConsecutiveFailures >= QuarantineAfter {
+		if !r.Quarantined() {
+			r.QuarantinedAt = now
+		}
+		r.NextProbe = now.Add(ReprobeInterval)
+	}
+}
+
+// Prune drops records for feeds that are no longer configured.
+func (s *State) Prune(keep map[string]bool) {
+	for feedURL := range s.Feeds {
+		if !keep[feedURL] {
+			delete(s.Feeds, feedURL)
+		}
+	}
+}
+
+// Finding is one unhealthy feed in a health report.
+type Finding struct {
+	FeedURL string
+	Record  *Record
+	Failing bool // failed at least the requested number of runs in a row
+	Stale   bool // no new item since the staleness cutoff
+}
+
+// Unhealthy returns feeds that failed at least failures runs in a row or whose
+// newest item predates staleBefore, most consecutive failures first.
+// Feeds that never succeeded have no item date and are reported as failing only.
+func (s *State) Unhealthy(failures int, staleBefore time.Time) []Finding {
+	var findings []Finding
+	for feedURL, rec := range s.Feeds {
+		f := Finding{
+			FeedURL: feedURL,
+			Record:  rec,
+			Failing: failures > 0 && rec.ConsecutiveFailures >= failures,
+			Stale:   !rec.LastItemDate.IsZero() && rec.LastItemDate.Before(staleBefore),
+		}
+		if f.Failing || f.Stale {
+			findings = append(findings, f)
+		}
+	}
+	sort.Slice(findings, func(i, j int) bool {
+		a, b := findings[i].Record, findings[j].Record
+		if a.ConsecutiveFailures != b.ConsecutiveFailures {
+			return a.ConsecutiveFailures > b.ConsecutiveFailures
+		}
+		if !a.LastItemDate.Equal(b.LastItemDate) {
+			return a.LastItemDate.Before(b.LastItemDate)
+		}
+		return findings[i].FeedURL < findings[j].FeedURL
+	})
+	return findings
+}
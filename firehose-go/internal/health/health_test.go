@@ -0,0 +1,179 @@
+package health
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/castrojo/firehose-go/internal/models"
+)
+
+const testFeed = "https://github.com/example/example/releases.atom"
+
+func success(entries int) []models.FeedStatus {
+	return []models.FeedStatus{{FeedURL: testFeed, Status: "success", EntriesCount: entries}}
+}
+
+func failure(msg string) []models.FeedStatus {
+	return []models.FeedStatus{{FeedURL: testFeed, Status: "error", ErrorType: "network", Error: msg}}
+}
+
+func TestUpdate(t *testing.T) {
+	day := 24 * time.Hour
+	start := time.Date(2026, 3, 1, 6, 0, 0, 0, time.UTC)
+	itemDate := start.Add(-3 * day)
+	state := NewState()
+
+	state.Update(KindRelease, success(10), []models.Release{
+		{FeedURL: testFeed, PubDate: itemDate},
+		{FeedURL: testFeed, PubDate: itemDate.Add(-day)},
+		{FeedURL: "https://other.example/feed", PubDate: start},
+	}, start)
+	state.Update(KindRelease, success(20), nil, start.Add(day))
+
+	rec := state.Feeds[testFeed]
+	if rec.Kind != KindRelease || rec.Successes != 2 || rec.AvgEntries != 15 {
+		t.Errorf("record = %+v, want 2 successes averaging 15 entries", rec)
+	}
+	if !rec.LastItemDate.Equal(itemDate) {
+		t.Errorf("LastItemDate = %v, want %v (kept across runs without items)", rec.LastItemDate, itemDate)
+	}
+
+	// Failures accumulate until the feed is quarantined.
+	var quarantined []string
+	for i := 1; i <= QuarantineAfter; i++ {
+		quarantined = state.Update(KindRelease, failure("timeout"), nil, start.Add(time.Duration(1+i)*day))
+		if i < QuarantineAfter && (len(quarantined) > 0 || rec.Quarantined()) {
+			t.Fatalf("quarantined after %d failures", i)
+		}
+	}
+	quarantinedAt := start.Add(time.Duration(1+QuarantineAfter) * day)
+	if len(quarantined) != 1 || quarantined[0] != testFeed {
+		t.Fatalf("Update() quarantined = %v, want [%s]", quarantined, testFeed)
+	}
+	if rec.ConsecutiveFailures != QuarantineAfter || len(rec.Errors) != QuarantineAfter {
+		t.Errorf("failures = %d, errors = %d; want %d each", rec.ConsecutiveFailures, len(rec.Errors), QuarantineAfter)
+	}
+	if !rec.LastSuccess.Equal(start.Add(day)) {
+		t.Errorf("LastSuccess = %v, want %v", rec.LastSuccess, start.Add(day))
+	}
+
+	// Skipped until the re-probe is due.
+	if !state.Skip(testFeed, quarantinedAt.Add(day)) {
+		t.Error("quarantined feed not skipped before its re-probe")
+	}
+	if state.Skip(testFeed, quarantinedAt.Add(ReprobeInterval)) {
+		t.Error("quarantined feed skipped when its re-probe is due")
+	}
+
+	// A failed re-probe keeps it quarantined and schedules the next one.
+	reprobe := quarantinedAt.Add(ReprobeInterval)
+	if q := state.Update(KindRelease, failure("timeout"), nil, reprobe); len(q) != 0 {
+		t.Errorf("already quarantined feed reported again: %v", q)
+	}
+	if !rec.QuarantinedAt.Equal(quarantinedAt) || !rec.NextProbe.Equal(reprobe.Add(ReprobeInterval)) {
+		t.Errorf("after failed re-probe: quarantinedAt = %v, nextProbe = %v", rec.QuarantinedAt, rec.NextProbe)
+	}
+
+	// A successful re-probe lifts the quarantine.
+	state.Update(KindRelease, success(5), nil, reprobe.Add(ReprobeInterval))
+	if rec.Quarantined() || rec.ConsecutiveFailures != 0 || state.Skip(testFeed, reprobe.Add(ReprobeInterval+day)) {
+		t.Errorf("quarantine not lifted after success: %+v", rec)
+	}
+}
+
+func TestActive(t *testing.T) {
+	start := time.Date(2026, 3, 1, 6, 0, 0, 0, time.UTC)
+	state := NewState()
+	const other = "https://github.com/example/other/releases.atom"
+	for i := 0; i < QuarantineAfter; i++ {
+		statuses := append(failure("timeout"), models.FeedStatus{FeedURL: other, Status: "error", Error: "timeout"})
+		state.Update(KindRelease, statuses, nil, start.Add(time.Duration(i)*time.Hour))
+	}
+
+	sources := []string{testFeed, other}
+	id := func(s string) string { return s }
+	mustSucceed := map[string]bool{testFeed: true}
+	// Both feeds are quarantined; only the must-succeed one is still fetched.
+	active, skipped := Active(state, sources, id, mustSucceed, start.Add(24*time.Hour))
+	if len(active) != 1 || active[0] != testFeed || skipped != 1 {
+		t.Errorf("Active() = %v, %d skipped; want [%s], 1 skipped", active, skipped, testFeed)
+	}
+	if active, skipped := Active(state, sources, id, nil, start.Add(24*time.Hour)); len(active) != 0 || skipped != 2 {
+		t.Errorf("Active() without must-succeed feeds = %v, %d skipped; want none active", active, skipped)
+	}
+}
+
+func TestErrorHistoryBounded(t *testing.T) {
+	state := NewState()
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < maxErrors+5; i++ {
+		state.Update(KindBlog, failure("HTTP 500"), nil, now.Add(time.Duration(i)*time.Hour))
+	}
+	rec := state.Feeds[testFeed]
+	if len(rec.Errors) != maxErrors {
+		t.Fatalf("kept %d errors, want %d", len(rec.Errors), maxErrors)
+	}
+	if want := now.Add(time.Duration(maxErrors+4) * time.Hour); !rec.Errors[maxErrors-1].At.Equal(want) {
+		t.Errorf("newest error at %v, want %v", rec.Errors[maxErrors-1].At, want)
+	}
+}
+
+func TestUnhealthy(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	state := &State{Feeds: map[string]*Record{
+		"https://healthy.example/feed": {LastItemDate: now.AddDate(0, -1, 0)},
+		"https://stale.example/feed":   {LastItemDate: now.AddDate(-1, 0, 0)},
+		"https://failing.example/feed": {ConsecutiveFailures: 4},
+		"https://flaky.example/feed":   {ConsecutiveFailures: 1, LastItemDate: now},
+		"https://both.example/feed":    {ConsecutiveFailures: 6, LastItemDate: now.AddDate(-2, 0, 0)},
+	}}
+
+	got := state.Unhealthy(3, now.AddDate(0, -6, 0))
+	want := []struct {
+		url            string
+		failing, stale bool
+	}{
+		{"https://both.example/feed", true, true},
+		{"https://failing.example/feed", true, false},
+		{"https://stale.example/feed", false, true},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Unhealthy() returned %d findings, want %d: %+v", len(got), len(want), got)
+	}
+	for i, w := range want {
+		if got[i].FeedURL != w.url || got[i].Failing != w.failing || got[i].Stale != w.stale {
+			t.Errorf("finding %d = %s (failing %v, stale %v), want %s (failing %v, stale %v)",
+				i, got[i].FeedURL, got[i].Failing, got[i].Stale, w.url, w.failing, w.stale)
+		}
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "feed-health.json")
+	state, err := Load(path)
+	if err != nil || len(state.Feeds) != 0 {
+		t.Fatalf("Load() of missing file = %+v, %v; want empty state", state, err)
+	}
+
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	state.Update(KindRelease, success(3), []models.Release{{FeedURL: testFeed, PubDate: now}}, now)
+	state.Prune(map[string]bool{testFeed: true})
+	if err := state.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	reloaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	rec := reloaded.Feeds[testFeed]
+	if rec == nil || rec.Successes != 1 || !rec.LastItemDate.Equal(now) {
+		t.Errorf("reloaded record = %+v", rec)
+	}
+
+	reloaded.Prune(map[string]bool{})
+	if len(reloaded.Feeds) != 0 {
+		t.Errorf("Prune() kept %d records", len(reloaded.Feeds))
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/castrojo/firehose-go/internal/metrics"
 	"github.com/castrojo/firehose-go/internal/models"
 	"github.com/castrojo/firehose-go/internal/urlutil"
 	"gopkg.in/yaml.v3"
@@ -16,7 +17,7 @@ const landscapeURL = "https://raw.githubusercontent.com/cncf/landscape/master/la
 // FetchAndParse fetches and parses the CNCF Landscape
 func FetchAndParse() (map[string]models.LandscapeProject, error) {
 	// Fetch landscape.yml with a 30s timeout to avoid hanging indefinitely.
-	client := &http.Client{Timeout: 30 * time.Second}
+	client := &http.Client{Transport: metrics.Default.Transport(nil), Timeout: 30 * time.Second}
 	resp, err := client.Get(landscapeURL)
 	if err != nil {
 		return nil, fmt.Errorf("fetch landscape: %w", err)
@@ -51,6 +52,8 @@ func parseLandscapeYAML(data []byte) (map[string]models.LandscapeProject, error)
 	projectMap := make(map[string]models.LandscapeProject)
 
 	for _, categoryMap := range doc.Landscape {
+		categoryName, _ := categoryMap["name"].(string)
+
 		// Parse subcategories array
 		subcategories, ok := categoryMap["subcategories"].([]interface{})
 		if !ok {
@@ -63,6 +66,8 @@ func parseLandscapeYAML(data []byte) (map[string]models.LandscapeProject, error)
 				continue
 			}
 
+			subcategoryName, _ := subMap["name"].(string)
+
 			// Parse items array
 			items, ok := subMap["items"].([]interface{})
 			if !ok {
@@ -82,10 +87,13 @@ func parseLandscapeYAML(data []byte) (map[string]models.LandscapeProject, error)
 				project, _ := itemMap["project"].(string)
 				description, _ := itemMap["description"].(string)
 
-				// blog_url and summary fields live inside extra:
-				var blogURL string
+				// blog_url, summary and milestone fields live inside extra:
+				var blogURL, accepted, incubating, graduated string
 				if extra, ok := itemMap["extra"].(map[string]interface{}); ok {
 					blogURL, _ = extra["blog_url"].(string)
+					accepted = dateValue(extra["accepted"])
+					incubating = dateValue(extra["incubating"])
+					graduated = dateValue(extra["graduated"])
 					if description == "" {
 						if summaryUseCase, ok := extra["summary_use_case"].(string); ok {
 							description = summaryUseCase
@@ -119,6 +127,11 @@ func parseLandscapeYAML(data []byte) (map[string]models.LandscapeProject, error)
 					HomepageURL: homepageURL,
 					Status:      project,
 					BlogURL:     blogURL,
+					Category:    categoryName,
+					Subcategory: subcategoryName,
+					Accepted:    accepted,
+					Incubating:  incubating,
+					Graduated:   graduated,
 				}
 			}
 		}
@@ -126,3 +139,17 @@ func parseLandscapeYAML(data []byte) (map[string]models.LandscapeProject, error)
 
 	return projectMap, nil
 }
+
+// dateValue formats a landscape date field as YYYY-MM-DD. Unquoted dates
+// decode as time.Time and quoted ones as strings; anything else is ignored.
+func dateValue(v interface{}) string {
+	switch d := v.(type) {
+	case time.Time:
+		return d.Format("2006-01-02")
+	case string:
+		if _, err := time.Parse("2006-01-02", d); err == nil {
+			return d
+		}
+	}
+	return ""
+}
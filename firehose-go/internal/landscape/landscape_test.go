@@ -33,6 +33,9 @@ landscape:
             project: "graduated"
             extra:
               blog_url: "https://prometheus.io/blog"
+              accepted: 2016-05-09
+              graduated: '2018-08-09'
+              incubating: not-a-date
       - name: "Incubating"
         items:
           - name: "Argo"
@@ -67,6 +70,9 @@ landscape:
 				if k8s.BlogURL != "" {
 					t.Errorf("expected no blog URL, got '%s'", k8s.BlogURL)
 				}
+				if k8s.Category != "CNCF Projects" || k8s.Subcategory != "Graduated" {
+					t.Errorf("expected category 'CNCF Projects / Graduated', got '%s / %s'", k8s.Category, k8s.Subcategory)
+				}
 
 				prom, ok := result["prometheus/prometheus"]
 				if !ok {
@@ -75,6 +81,10 @@ landscape:
 				if prom.BlogURL != "https://prometheus.io/blog" {
 					t.Errorf("expected blog URL 'https://prometheus.io/blog', got '%s'", prom.BlogURL)
 				}
+				if prom.Accepted != "2016-05-09" || prom.Graduated != "2018-08-09" || prom.Incubating != "" {
+					t.Errorf("expected milestones accepted 2016-05-09, graduated 2018-08-09, no incubating; got %q, %q, %q",
+						prom.Accepted, prom.Graduated, prom.Incubating)
+				}
 
 				argo, ok := result["argoproj/argo-workflows"]
 				if !ok {
@@ -0,0 +1,199 @@
+// Package metrics collects pipeline timing and HTTP traffic for Metadata.Performance.
+//
+// A Collector records wall-clock time per pipeline stage and, through the
+// RoundTripper returned by Transport, per-host latency, bytes and request
+// counts. Retries are reported by the fetchers via Retry.
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/castrojo/firehose-go/internal/models"
+)
+
+// Pipeline stage names.
+const (
+	StageLandscape  = "landscape"
+	StageFeeds      = "feeds"
+	StageBlogs      = "blogs"
+	StageEnrichment = "enrichment" // summed across concurrent feed fetches
+	StageValidation = "validation"
+	StageOutput     = "output"
+)
+
+// Default is the process-wide collector shared by the pipeline and its fetchers.
+var Default = New()
+
+// Collector accumulates stage durations and per-host HTTP statistics.
+type Collector struct {
+	mu     sync.Mutex
+	stages map[string]time.Duration
+	hosts  map[string]*hostStats
+}
+
+type hostStats struct {
+	requests  int
+	errors    int
+	retries   int
+	bytes     int64
+	latencies []time.Duration // time to response headers
+}
+
+// New returns an empty collector.
+func New() *Collector {
+	return &Collector{stages: make(map[string]time.Duration), hosts: make(map[string]*hostStats)}
+}
+
+// Start begins timing stage and returns a function that stops it.
+// Timing the same stage again adds to its total.
+func (c *Collector) Start(stage string) (stop func()) {
+	start := time.Now()
+	return func() { c.Add(stage, time.Since(start)) }
+}
+
+// Add adds d to stage's total. It is safe for concurrent use.
+func (c *Collector) Add(stage string, d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stages[stage] += d
+}
+
+// Stage returns the total time recorded for stage.
+func (c *Collector) Stage(stage string) time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stages[stage]
+}
+
+// Retry records a retried request to rawURL's host.
+func (c *Collector) Retry(rawURL string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.host(hostOf(rawURL)).retries++
+}
+
+// host returns the stats for host; c.mu must be held.
+func (c *Collector) host(host string) *hostStats {
+	h, ok := c.hosts[host]
+	if !ok {
+		h = &hostStats{}
+		c.hosts[host] = h
+	}
+	return h
+}
+
+// Transport returns a RoundTripper that records every request sent through
+// base. A nil base uses http.DefaultTransport.
+func (c *Collector) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return roundTripper{c: c, base: base}
+}
+
+type roundTripper struct {
+	c    *Collector
+	base http.RoundTripper
+}
+
+func (rt roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := rt.base.RoundTrip(req)
+	latency := time.Since(start)
+
+	rt.c.mu.Lock()
+	h := rt.c.host(req.URL.Host)
+	h.requests++
+	h.latencies = append(h.latencies, latency)
+	if err != nil || resp.StatusCode >= 400 {
+		h.errors++
+	}
+	rt.c.mu.Unlock()
+
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = &countingBody{ReadCloser: resp.Body, c: rt.c, host: req.URL.Host}
+	return resp, nil
+}
+
+// countingBody adds the bytes read from a response body to its host's total.
+type countingBody struct {
+	io.ReadCloser
+	c    *Collector
+	host string
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if n > 0 {
+		b.c.mu.Lock()
+		b.c.host(b.host).bytes += int64(n)
+		b.c.mu.Unlock()
+	}
+	return n, err
+}
+
+// Performance returns the recorded stage timings and HTTP statistics.
+// Stages that were not timed are reported as "0s".
+func (c *Collector) Performance() models.Performance {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	p := models.Performance{
+		LandscapeFetchDuration: c.stages[StageLandscape].String(),
+		FeedsFetchDuration:     c.stages[StageFeeds].String(),
+		BlogFetchDuration:      c.stages[StageBlogs].String(),
+		EnrichmentDuration:     c.stages[StageEnrichment].String(),
+		ValidationDuration:     c.stages[StageValidation].String(),
+	}
+	if d, ok := c.stages[StageOutput]; ok {
+		p.OutputDuration = d.String()
+	}
+
+	for host, h := range c.hosts {
+		p.Requests += h.requests
+		p.BytesTransferred += h.bytes
+		p.Retries += h.retries
+		p.Hosts = append(p.Hosts, models.HostPerformance{
+			Host:     host,
+			Requests: h.requests,
+			Errors:   h.errors,
+			Retries:  h.retries,
+			Bytes:    h.bytes,
+			P50:      percentile(h.latencies, 50),
+			P90:      percentile(h.latencies, 90),
+			P99:      percentile(h.latencies, 99),
+		})
+	}
+	sort.Slice(p.Hosts, func(i, j int) bool {
+		if p.Hosts[i].Requests != p.Hosts[j].Requests {
+			return p.Hosts[i].Requests > p.Hosts[j].Requests
+		}
+		return p.Hosts[i].Host < p.Hosts[j].Host
+	})
+	return p
+}
+
+// percentile returns the nearest-rank percentile of latencies, rounded to the millisecond.
+func percentile(latencies []time.Duration, pct int) string {
+	if len(latencies) == 0 {
+		return ""
+	}
+	sorted := append([]time.Duration(nil), latencies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	rank := (pct*len(sorted) + 99) / 100 // ceil(pct/100 × n)
+	return sorted[max(rank, 1)-1].Round(time.Millisecond).String()
+}
+
+func hostOf(rawURL string) string {
+	if u, err := url.Parse(rawURL); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return rawURL
+}
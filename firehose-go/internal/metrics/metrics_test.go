@@ -0,0 +1,111 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStages(t *testing.T) {
+	c := New()
+	c.Add(StageEnrichment, 2*time.Millisecond)
+	c.Add(StageEnrichment, 3*time.Millisecond)
+	stop := c.Start(StageFeeds)
+	time.Sleep(5 * time.Millisecond)
+	stop()
+
+	if got := c.Stage(StageEnrichment); got != 5*time.Millisecond {
+		t.Errorf("enrichment = %v, want 5ms", got)
+	}
+	if got := c.Stage(StageFeeds); got < 5*time.Millisecond {
+		t.Errorf("feeds = %v, want at least 5ms", got)
+	}
+
+	p := c.Performance()
+	if p.EnrichmentDuration != "5ms" || p.ValidationDuration != "0s" {
+		t.Errorf("Performance() = %+v", p)
+	}
+	if p.OutputDuration != "" {
+		t.Errorf("OutputDuration = %q before the output stage ran, want empty", p.OutputDuration)
+	}
+}
+
+func TestTransport(t *testing.T) {
+	body := strings.Repeat("x", 1000)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		io.WriteString(w, body)
+	}))
+	defer server.Close()
+
+	c := New()
+	client := &http.Client{Transport: c.Transport(nil)}
+	for _, path := range []string{"/a", "/b", "/missing"} {
+		resp, err := client.Get(server.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}
+	c.Retry(server.URL + "/a")
+
+	p := c.Performance()
+	host := mustHost(t, server.URL)
+	if len(p.Hosts) != 1 || p.Hosts[0].Host != host {
+		t.Fatalf("Hosts = %+v, want one entry for %s", p.Hosts, host)
+	}
+	h := p.Hosts[0]
+	if h.Requests != 3 || h.Errors != 1 || h.Retries != 1 || h.Bytes != 2000 {
+		t.Errorf("host stats = %+v, want 3 requests, 1 error, 1 retry, 2000 bytes", h)
+	}
+	if h.P50 == "" || h.P99 == "" {
+		t.Errorf("missing latency percentiles: %+v", h)
+	}
+	if p.Requests != 3 || p.BytesTransferred != 2000 || p.Retries != 1 {
+		t.Errorf("totals = %d requests, %d bytes, %d retries", p.Requests, p.BytesTransferred, p.Retries)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	var latencies []time.Duration
+	for i := 100; i >= 1; i-- {
+		latencies = append(latencies, time.Duration(i)*time.Millisecond)
+	}
+	tests := []struct {
+		pct  int
+		want string
+	}{
+		{50, "50ms"},
+		{90, "90ms"},
+		{99, "99ms"},
+		{100, "100ms"},
+	}
+	for _, tt := range tests {
+		if got := percentile(latencies, tt.pct); got != tt.want {
+			t.Errorf("percentile(%d) = %s, want %s", tt.pct, got, tt.want)
+		}
+	}
+	if got := percentile([]time.Duration{7 * time.Millisecond}, 99); got != "7ms" {
+		t.Errorf("single sample p99 = %s, want 7ms", got)
+	}
+	if got := percentile(nil, 50); got != "" {
+		t.Errorf("empty p50 = %q, want empty", got)
+	}
+}
+
+func mustHost(t *testing.T, rawURL string) string {
+	t.Helper()
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u.Host
+}
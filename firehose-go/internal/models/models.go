@@ -3,11 +3,21 @@ package models
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
+
+	"github.com/castrojo/firehose-go/internal/atomicfile"
 )
 
+// SchemaVersion is the version of the output contract described by the
+// generated JSON Schema. Bump the major version for breaking changes (removed
+// or retyped properties, properties that became optional, new enum values)
+// and the minor version for additions.
+const SchemaVersion = "1.1.0"
+
 // OutputData represents the top-level JSON structure
 type OutputData struct {
 	Metadata Metadata     `json:"metadata"`
@@ -32,20 +42,43 @@ type Stats struct {
 	FeedsSuccessful          int `json:"feedsSuccessful"`
 	FeedsFailed              int `json:"feedsFailed"`
 	FeedsSkipped             int `json:"feedsSkipped"`
+	FeedsWithWarnings        int `json:"feedsWithWarnings"`
+	InvalidRecords           int `json:"invalidRecords"` // Records dropped for failing validation
 	ReleasesTotal            int `json:"releasesTotal"`
 	NewsTotal                int `json:"newsTotal"`
 	BlogFeedsTotal           int `json:"blogFeedsTotal"`
 	LandscapeProjectsTotal   int `json:"landscapeProjectsTotal"`
 	LandscapeProjectsMatched int `json:"landscapeProjectsMatched"`
+	// Warnings counts the feeds affected by each lint warning code
+	Warnings map[string]int `json:"warnings,omitempty"`
 }
 
-// Performance contains timing breakdown
+// Performance contains timing breakdown and HTTP traffic statistics
 type Performance struct {
 	LandscapeFetchDuration string `json:"landscapeFetchDuration"`
 	FeedsFetchDuration     string `json:"feedsFetchDuration"`
-	EnrichmentDuration     string `json:"enrichmentDuration"`
+	BlogFetchDuration      string `json:"blogFetchDuration"`
+	EnrichmentDuration     string `json:"enrichmentDuration"` // Summed across concurrent feed fetches
 	ValidationDuration     string `json:"validationDuration"`
-	OutputDuration         string `json:"outputDuration"`
+	// OutputDuration is only known after the output is written, so it is
+	// recorded in the performance sidecar rather than in the output itself.
+	OutputDuration   string            `json:"outputDuration,omitempty"`
+	Requests         int               `json:"requests"`
+	BytesTransferred int64             `json:"bytesTransferred"`
+	Retries          int               `json:"retries"`
+	Hosts            []HostPerformance `json:"hosts,omitempty"` // Most requests first
+}
+
+// HostPerformance summarizes HTTP traffic to one host; latencies are time to response headers
+type HostPerformance struct {
+	Host     string `json:"host"`
+	Requests int    `json:"requests"`
+	Errors   int    `json:"errors"`
+	Retries  int    `json:"retries"`
+	Bytes    int64  `json:"bytes"`
+	P50      string `json:"p50"`
+	P90      string `json:"p90"`
+	P99      string `json:"p99"`
 }
 
 // Release represents a single release entry
@@ -65,17 +98,36 @@ type Release struct {
 	FeedTitle          string    `json:"feedTitle,omitempty"`
 	FeedStatus         string    `json:"feedStatus" validate:"required,oneof=success error"`
 	FetchedAt          time.Time `json:"fetchedAt" validate:"required"`
+	// Enclosures are media attached to the item (podcast audio, images), as published by the feed
+	Enclosures []Enclosure `json:"enclosures,omitempty"`
+}
+
+// Enclosure is a media file attached to a feed item
+type Enclosure struct {
+	URL    string `json:"url" validate:"required,url"`
+	Type   string `json:"type,omitempty"`   // MIME type
+	Length int64  `json:"length,omitempty"` // Size in bytes, when the feed gives one
 }
 
 // FeedStatus tracks feed fetch results
 type FeedStatus struct {
 	FeedURL      string `json:"feedUrl" validate:"required,url"`
+	FinalURL     string `json:"finalUrl,omitempty" validate:"omitempty,url"` // Set when the feed redirected elsewhere
 	Status       string `json:"status" validate:"required,oneof=success error"`
 	EntriesCount int    `json:"entriesCount,omitempty"`
 	Error        string `json:"error,omitempty"`
 	ErrorType    string `json:"errorType,omitempty" validate:"omitempty,oneof=network parse validation timeout"`
 	FetchedAt    string `json:"fetchedAt" validate:"required"`
 	Duration     string `json:"duration" validate:"required"`
+	// Warnings are lint findings for a feed that loaded but has quality problems
+	Warnings []FeedWarning `json:"warnings,omitempty"`
+}
+
+// FeedWarning is one lint finding for a feed, aggregated over its items
+type FeedWarning struct {
+	Code    string `json:"code" validate:"required"`
+	Count   int    `json:"count"`             // Number of offending items
+	Example string `json:"example,omitempty"` // First offending item, GUID or link
 }
 
 // LandscapeProject represents a CNCF project from landscape.yml
@@ -86,12 +138,74 @@ type LandscapeProject struct {
 	HomepageURL string `json:"homepage_url,omitempty"`
 	Status      string `json:"project,omitempty"` // graduated, incubating, sandbox
 	BlogURL     string `json:"blog_url,omitempty"`
+	Category    string `json:"category,omitempty"`    // Landscape category, e.g. "Orchestration & Management"
+	Subcategory string `json:"subcategory,omitempty"` // Landscape subcategory, e.g. "Scheduling & Orchestration"
+	// CNCF milestone dates (YYYY-MM-DD) from the landscape's extra fields; empty when not reached
+	Accepted   string `json:"accepted,omitempty"`
+	Incubating string `json:"incubating,omitempty"`
+	Graduated  string `json:"graduated,omitempty"`
 }
 
 // FeedConfig represents the feeds.yaml configuration
 type FeedConfig struct {
-	Feeds []FeedSource `yaml:"feeds"`
-	Blogs []BlogSource `yaml:"blogs,omitempty"`
+	UserAgent  string           `yaml:"user_agent,omitempty"` // Sent with every request and matched against robots.txt
+	Anomalies  AnomalyConfig    `yaml:"anomalies,omitempty"`
+	Policy     PolicyConfig     `yaml:"policy,omitempty"`
+	Validation ValidationConfig `yaml:"validation,omitempty"`
+	Output     OutputConfig     `yaml:"output,omitempty"`
+	Feeds      []FeedSource     `yaml:"feeds"`
+	Blogs      []BlogSource     `yaml:"blogs,omitempty"`
+}
+
+// OutputConfig selects additional output forms written next to releases.json
+type OutputConfig struct {
+	Shards bool `yaml:"shards,omitempty"` // Also write releases/index.json with per-project and per-month shards
+	// Feeds writes Atom, RSS 2.0 and JSON Feed files (overall, per tier, per
+	// project and news) under public/feeds/ so the site serves them
+	Feeds bool `yaml:"feeds,omitempty"`
+	// Calendars writes iCalendar files of releases and CNCF maturity
+	// milestones (overall, per tier, per project) under public/calendars/
+	Calendars bool `yaml:"calendars,omitempty"`
+	// SQLite is the path of a SQLite database (relative to firehose-go/) to
+	// record each run in: projects, feeds, releases, news and fetch history
+	SQLite    string `yaml:"sqlite,omitempty"`
+	FeedItems int    `yaml:"feed_items,omitempty"`                        // Most recent items per feed (default 500)
+	SiteURL   string `yaml:"site_url,omitempty" validate:"omitempty,url"` // Public site root for feed links (default https://castrojo.github.io/firehose/)
+}
+
+// ValidationConfig sets what happens to records that fail their validate tags
+type ValidationConfig struct {
+	// Invalid is "drop" (default) or "quarantine", which also saves them to
+	// state/invalid-records.json for inspection
+	Invalid string `yaml:"invalid,omitempty" validate:"omitempty,oneof=drop quarantine"`
+}
+
+// PolicyConfig sets the success thresholds a run must meet to publish
+type PolicyConfig struct {
+	Release     KindPolicy `yaml:"release,omitempty"`      // Defaults to min_success_rate 0.5
+	Blog        KindPolicy `yaml:"blog,omitempty"`         // No threshold by default
+	MustSucceed []string   `yaml:"must_succeed,omitempty"` // Feed URLs that fail the run on their own
+}
+
+// KindPolicy holds the thresholds for one feed kind; rates are fractions (0.9 = 90%)
+type KindPolicy struct {
+	MinSuccessRate *float64           `yaml:"min_success_rate,omitempty"`
+	Categories     map[string]float64 `yaml:"categories,omitempty"` // graduated/incubating/sandbox → minimum success rate
+}
+
+// AnomalyConfig tunes anomaly detection on feed results
+type AnomalyConfig struct {
+	HoldBursts  bool          `yaml:"hold_bursts,omitempty"`  // Withhold burst items from output until reviewed
+	BurstItems  int           `yaml:"burst_items,omitempty"`  // New items within burst_window that count as a burst (default: half the feed's usual item count, 5 to 10)
+	BurstWindow time.Duration `yaml:"burst_window,omitempty"` // e.g. "1h" (default)
+	AllowBursts []BurstReview `yaml:"allow_bursts,omitempty"` // Reviewed feeds whose held items are released
+}
+
+// BurstReview releases the burst items of one feed that were held at or before
+// Reviewed; bursts found later are held again.
+type BurstReview struct {
+	Feed     string    `yaml:"feed"`
+	Reviewed time.Time `yaml:"reviewed"` // RFC 3339, e.g. the time logged when the items were held
 }
 
 // FeedSource represents a single feed source
@@ -99,6 +213,7 @@ type FeedSource struct {
 	URL      string  `yaml:"url" validate:"required,url"`
 	Category string  `yaml:"category" validate:"required,oneof=graduated incubating sandbox"`
 	Project  *string `yaml:"project,omitempty"` // Optional project name override
+	Pinned   bool    `yaml:"pinned,omitempty"`  // Added by hand or imported; landscape sync never removes or edits it
 }
 
 // BlogSource represents a single blog feed source
@@ -107,28 +222,68 @@ type BlogSource struct {
 	Category string `yaml:"category"`
 	Project  string `yaml:"project,omitempty"`
 	BlogURL  string `yaml:"blog_url,omitempty"`
+	Pinned   bool   `yaml:"pinned,omitempty"` // Added by hand or imported; landscape sync never removes or edits it
 }
 
-// WriteJSON writes OutputData to a JSON file (pretty-printed)
+// WriteJSON writes OutputData to a JSON file (pretty-printed).
+// The write is atomic: a crash or encode error leaves the previous file in place.
 func (o *OutputData) WriteJSON(path string) error {
-	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
-		return fmt.Errorf("create output directory: %w", err)
+	return atomicfile.Write(path, 0644, func(w io.Writer) error {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		encoder.SetEscapeHTML(false) // Keep URLs readable
+
+		if err := encoder.Encode(o); err != nil {
+			return fmt.Errorf("encode JSON: %w", err)
+		}
+		return nil
+	}, atomicfile.VerifyJSON[OutputData]())
+}
+
+// PerformancePath returns the performance sidecar path for an output path:
+// releases.json → releases.performance.json.
+func PerformancePath(outputPath string) string {
+	return strings.TrimSuffix(outputPath, filepath.Ext(outputPath)) + ".performance.json"
+}
+
+// SchemaPath returns the path of the JSON Schema shipped with an output file:
+// releases.json → releases.schema.json.
+func SchemaPath(outputPath string) string {
+	return strings.TrimSuffix(outputPath, filepath.Ext(outputPath)) + ".schema.json"
+}
+
+// WritePerformance writes the run's final timing, including the output write
+// that the output file itself cannot contain, to the performance sidecar.
+func (o *OutputData) WritePerformance(path string) error {
+	sidecar := struct {
+		GeneratedAt   string      `json:"generatedAt"`
+		BuildDuration string      `json:"buildDuration"`
+		Performance   Performance `json:"performance"`
+	}{o.Metadata.GeneratedAt, o.Metadata.BuildDuration, o.Metadata.Performance}
+
+	data, err := json.MarshalIndent(sidecar, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode performance: %w", err)
+	}
+	if err := atomicfile.WriteFile(path, append(data, '\n'), 0644, nil); err != nil {
+		return fmt.Errorf("write performance: %w", err)
 	}
-	file, err := os.Create(path)
+	return nil
+}
+
+// ReadJSON reads OutputData from a JSON file written by WriteJSON
+func ReadJSON(path string) (*OutputData, error) {
+	file, err := os.Open(path)
 	if err != nil {
-		return fmt.Errorf("create file: %w", err)
+		return nil, fmt.Errorf("open file: %w", err)
 	}
 	defer file.Close()
 
-	encoder := json.NewEncoder(file)
-	encoder.SetIndent("", "  ")
-	encoder.SetEscapeHTML(false) // Keep URLs readable
-
-	if err := encoder.Encode(o); err != nil {
-		return fmt.Errorf("encode JSON: %w", err)
+	var o OutputData
+	if err := json.NewDecoder(file).Decode(&o); err != nil {
+		return nil, fmt.Errorf("decode JSON: %w", err)
 	}
-
-	return nil
+	return &o, nil
 }
 
 // FetchResults holds the results of parallel feed fetching
@@ -0,0 +1,252 @@
+package models
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/castrojo/firehose-go/internal/atomicfile"
+)
+
+// UnmatchedProjectSlug names the project shard for items without a landscape
+// project. Slugs never start with "_", so it cannot collide with a project.
+const UnmatchedProjectSlug = "_unmatched"
+
+// ShardIndex is index.json of a sharded output: everything in OutputData
+// except the items, plus a manifest of the shards holding them
+type ShardIndex struct {
+	Metadata Metadata        `json:"metadata"`
+	Feeds    []FeedStatus    `json:"feeds"`
+	Projects []ShardManifest `json:"projects"` // Sorted by slug
+	Months   []ShardManifest `json:"months"`   // Newest first
+}
+
+// ShardManifest describes one shard file so clients can pick shards and
+// detect changes without downloading them
+type ShardManifest struct {
+	Key      string    `json:"key"`  // Project name, or yyyy-mm for month shards
+	Path     string    `json:"path"` // Relative to index.json, e.g. "months/2026-01.json"
+	Releases int       `json:"releases"`
+	News     int       `json:"news"`
+	SHA256   string    `json:"sha256"` // Hex digest of the shard file
+	Oldest   time.Time `json:"oldest"` // Earliest pubDate in the shard
+	Newest   time.Time `json:"newest"` // Latest pubDate in the shard
+}
+
+// Shard is one projects/<slug>.json or months/<yyyy-mm>.json file
+type Shard struct {
+	Key      string    `json:"key"`
+	Releases []Release `json:"releases"`
+	News     []Release `json:"news"`
+}
+
+// ShardPublicDir is the directory under the site root that shards are served from.
+const ShardPublicDir = "releases"
+
+// ShardDir returns the directory the shards are written to under the site's
+// static directory, so they are published with it: public/ → public/releases/.
+func ShardDir(publicDir string) string {
+	return filepath.Join(publicDir, ShardPublicDir)
+}
+
+// WriteShards writes the output split into projects/<slug>.json and
+// months/<yyyy-mm>.json under dir, then index.json. Each file is written
+// atomically and index.json goes last, so a reader following the index only
+// sees shards it describes. Shards left over from earlier runs are removed.
+func (o *OutputData) WriteShards(dir string) (*ShardIndex, error) {
+	byProject := make(map[string]*Shard)
+	byMonth := make(map[string]*Shard)
+	add := func(groups map[string]*Shard, slug, key string, rel Release, news bool) {
+		s, ok := groups[slug]
+		if !ok {
+			s = &Shard{Key: key, Releases: []Release{}, News: []Release{}}
+			groups[slug] = s
+		}
+		if news {
+			s.News = append(s.News, rel)
+		} else {
+			s.Releases = append(s.Releases, rel)
+		}
+	}
+	var names []string
+	for _, list := range [][]Release{o.Releases, o.News} {
+		for _, rel := range list {
+			names = append(names, rel.ProjectName)
+		}
+	}
+	slugs := projectSlugs(names)
+	for _, items := range []struct {
+		list []Release
+		news bool
+	}{{o.Releases, false}, {o.News, true}} {
+		for _, rel := range items.list {
+			slug, key := slugs[rel.ProjectName], rel.ProjectName
+			if slug == "" {
+				slug, key = UnmatchedProjectSlug, ""
+			}
+			add(byProject, slug, key, rel, items.news)
+			month := rel.PubDate.UTC().Format("2006-01")
+			add(byMonth, month, month, rel, items.news)
+		}
+	}
+
+	index := &ShardIndex{Metadata: o.Metadata, Feeds: o.Feeds}
+	var err error
+	if index.Projects, err = writeShardGroup(dir, "projects", byProject); err != nil {
+		return nil, err
+	}
+	if index.Months, err = writeShardGroup(dir, "months", byMonth); err != nil {
+		return nil, err
+	}
+	sort.Slice(index.Months, func(i, j int) bool { return index.Months[i].Key > index.Months[j].Key })
+
+	data, err := encodeJSON(index)
+	if err != nil {
+		return nil, err
+	}
+	if err := atomicfile.WriteFile(filepath.Join(dir, "index.json"), data, 0644, atomicfile.VerifyJSON[ShardIndex]()); err != nil {
+		return nil, fmt.Errorf("write shard index: %w", err)
+	}
+	return index, nil
+}
+
+// writeShardGroup writes one shard per slug into dir/group, removes other
+// .json files there, and returns the manifests sorted by slug.
+func writeShardGroup(dir, group string, shards map[string]*Shard) ([]ShardManifest, error) {
+	slugs := make([]string, 0, len(shards))
+	for slug := range shards {
+		slugs = append(slugs, slug)
+	}
+	sort.Strings(slugs)
+
+	manifests := make([]ShardManifest, 0, len(slugs))
+	keep := make(map[string]bool, len(slugs))
+	for _, slug := range slugs {
+		s := shards[slug]
+		data, err := encodeJSON(s)
+		if err != nil {
+			return nil, err
+		}
+		name := slug + ".json"
+		if err := atomicfile.WriteFile(filepath.Join(dir, group, name), data, 0644, atomicfile.VerifyJSON[Shard]()); err != nil {
+			return nil, fmt.Errorf("write shard %s/%s: %w", group, name, err)
+		}
+		keep[name] = true
+
+		sum := sha256.Sum256(data)
+		m := ShardManifest{
+			Key:      s.Key,
+			Path:     group + "/" + name,
+			Releases: len(s.Releases),
+			News:     len(s.News),
+			SHA256:   hex.EncodeToString(sum[:]),
+		}
+		for _, list := range [][]Release{s.Releases, s.News} {
+			for _, rel := range list {
+				if m.Oldest.IsZero() || rel.PubDate.Before(m.Oldest) {
+					m.Oldest = rel.PubDate
+				}
+				if rel.PubDate.After(m.Newest) {
+					m.Newest = rel.PubDate
+				}
+			}
+		}
+		manifests = append(manifests, m)
+	}
+
+	stale, err := filepath.Glob(filepath.Join(dir, group, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("list %s shards: %w", group, err)
+	}
+	for _, path := range stale {
+		if !keep[filepath.Base(path)] {
+			if err := os.Remove(path); err != nil {
+				return nil, fmt.Errorf("remove stale shard: %w", err)
+			}
+		}
+	}
+	return manifests, nil
+}
+
+// projectSlugs maps each distinct project name to a unique shard slug. Names
+// whose Slug collides ("Foo.io" and "Foo IO") are taken in sorted order: the
+// first keeps the slug and the others get "-2", "-3", … appended, skipping
+// slugs another project already has. Names without a slug map to "".
+func projectSlugs(names []string) map[string]string {
+	bySlug := make(map[string][]string)
+	for _, name := range names {
+		slug := Slug(name)
+		if slug == "" {
+			continue
+		}
+		if !slices.Contains(bySlug[slug], name) {
+			bySlug[slug] = append(bySlug[slug], name)
+		}
+	}
+	bases := make([]string, 0, len(bySlug))
+	used := make(map[string]bool, len(bySlug))
+	for slug := range bySlug {
+		bases = append(bases, slug)
+		used[slug] = true
+	}
+	sort.Strings(bases)
+
+	slugs := make(map[string]string)
+	for _, base := range bases {
+		group := bySlug[base]
+		sort.Strings(group)
+		slugs[group[0]] = base
+		n := 2
+		for _, name := range group[1:] {
+			for {
+				candidate := fmt.Sprintf("%s-%d", base, n)
+				n++
+				if !used[candidate] {
+					slugs[name], used[candidate] = candidate, true
+					break
+				}
+			}
+		}
+	}
+	return slugs
+}
+
+// Slug converts a project name to a file name: lowercase ASCII letters and
+// digits, with every other run of characters replaced by a single "-".
+// Names that differ only in punctuation share a slug; see projectSlugs.
+func Slug(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+		} else {
+			dash = true
+		}
+	}
+	return b.String()
+}
+
+// encodeJSON encodes v the way WriteJSON does: indented, URLs unescaped.
+func encodeJSON(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(v); err != nil {
+		return nil, fmt.Errorf("encode JSON: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,134 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSlug(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Kubernetes", "kubernetes"},
+		{"Open Policy Agent (OPA)", "open-policy-agent-opa"},
+		{"  cert-manager ", "cert-manager"},
+		{"KEDA.sh", "keda-sh"},
+		{"", ""},
+		{"日本", ""},
+	}
+	for _, tt := range tests {
+		if got := Slug(tt.name); got != tt.want {
+			t.Errorf("Slug(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestProjectSlugs(t *testing.T) {
+	got := projectSlugs([]string{"Foo IO", "Foo.io", "Foo IO", "foo-io-2", "Kubernetes", "", "日本"})
+	want := map[string]string{
+		"Foo IO":     "foo-io",
+		"Foo.io":     "foo-io-3", // foo-io-2 belongs to another project
+		"foo-io-2":   "foo-io-2",
+		"Kubernetes": "kubernetes",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("projectSlugs() = %v, want %v", got, want)
+	}
+}
+
+func TestShardDir(t *testing.T) {
+	// Shards must land in the site's static directory to be published.
+	if got, want := ShardDir("../public"), filepath.Join("..", "public", "releases"); got != want {
+		t.Errorf("ShardDir() = %q, want %q", got, want)
+	}
+}
+
+func TestWriteShards(t *testing.T) {
+	dir := t.TempDir()
+	jan := time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)
+	feb := time.Date(2026, 2, 3, 0, 0, 0, 0, time.UTC)
+	out := &OutputData{
+		Metadata: Metadata{SchemaVersion: SchemaVersion},
+		Releases: []Release{
+			{ID: "1", ProjectName: "Kubernetes", PubDate: feb},
+			{ID: "2", ProjectName: "Kubernetes", PubDate: jan},
+			{ID: "3", PubDate: jan},
+		},
+		News:  []Release{{ID: "4", ProjectName: "Kubernetes", PubDate: jan}},
+		Feeds: []FeedStatus{{FeedURL: "https://example.com/feed", Status: "success"}},
+	}
+
+	// A shard from an earlier run that no longer has items.
+	stale := filepath.Join(dir, "months", "2025-12.json")
+	if err := os.MkdirAll(filepath.Dir(stale), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(stale, []byte("{}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	index, err := out.WriteShards(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(index.Projects) != 2 || index.Projects[0].Path != "projects/_unmatched.json" || index.Projects[1].Path != "projects/kubernetes.json" {
+		t.Fatalf("projects = %+v", index.Projects)
+	}
+	k8s := index.Projects[1]
+	if k8s.Key != "Kubernetes" || k8s.Releases != 2 || k8s.News != 1 || !k8s.Oldest.Equal(jan) || !k8s.Newest.Equal(feb) {
+		t.Errorf("kubernetes manifest = %+v", k8s)
+	}
+	if len(index.Months) != 2 || index.Months[0].Key != "2026-02" || index.Months[1].Releases != 2 || index.Months[1].News != 1 {
+		t.Errorf("months = %+v", index.Months)
+	}
+	if len(index.Feeds) != 1 || index.Metadata.SchemaVersion != SchemaVersion {
+		t.Errorf("index lost metadata or feeds: %+v", index)
+	}
+
+	for _, m := range append(index.Projects, index.Months...) {
+		data, err := os.ReadFile(filepath.Join(dir, m.Path))
+		if err != nil {
+			t.Fatal(err)
+		}
+		sum := sha256.Sum256(data)
+		if hex.EncodeToString(sum[:]) != m.SHA256 {
+			t.Errorf("%s: hash does not match manifest", m.Path)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "index.json")); err != nil {
+		t.Errorf("index.json not written: %v", err)
+	}
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Errorf("stale shard not removed: %v", err)
+	}
+}
+
+func TestWriteShardsSlugCollision(t *testing.T) {
+	dir := t.TempDir()
+	day := time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)
+	out := &OutputData{Releases: []Release{
+		{ID: "1", ProjectName: "Foo.io", PubDate: day},
+		{ID: "2", ProjectName: "Foo IO", PubDate: day},
+		{ID: "3", ProjectName: "Foo IO", PubDate: day},
+	}}
+
+	index, err := out.WriteShards(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]ShardManifest)
+	for _, m := range index.Projects {
+		got[m.Path] = m
+	}
+	if len(got) != 2 || got["projects/foo-io.json"].Key != "Foo IO" || got["projects/foo-io.json"].Releases != 2 ||
+		got["projects/foo-io-2.json"].Key != "Foo.io" || got["projects/foo-io-2.json"].Releases != 1 {
+		t.Errorf("projects = %+v, want Foo IO and Foo.io in separate shards", index.Projects)
+	}
+}
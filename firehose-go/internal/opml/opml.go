@@ -0,0 +1,233 @@
+// Package opml reads and writes OPML 2.0 subscription lists
+// (http://opml.org/spec2.opml) and builds one from feeds.yaml.
+package opml
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/castrojo/firehose-go/internal/models"
+	"github.com/castrojo/firehose-go/internal/urlutil"
+)
+
+// Document is an OPML 2.0 document.
+type Document struct {
+	XMLName xml.Name `xml:"opml"`
+	Version string   `xml:"version,attr"`
+	Head    Head     `xml:"head"`
+	Body    Body     `xml:"body"`
+}
+
+// Head holds the document metadata.
+type Head struct {
+	Title       string `xml:"title"`
+	DateCreated string `xml:"dateCreated,omitempty"` // RFC 822
+	Docs        string `xml:"docs,omitempty"`
+}
+
+// Body holds the top-level outlines.
+type Body struct {
+	Outlines []Outline `xml:"outline"`
+}
+
+// Outline is a folder (with children) or, when XMLURL is set, a subscription.
+type Outline struct {
+	Text        string    `xml:"text,attr"`
+	Title       string    `xml:"title,attr,omitempty"`
+	Type        string    `xml:"type,attr,omitempty"` // "rss" for subscriptions, whatever the feed format
+	XMLURL      string    `xml:"xmlUrl,attr,omitempty"`
+	HTMLURL     string    `xml:"htmlUrl,attr,omitempty"`
+	Description string    `xml:"description,attr,omitempty"`
+	Category    string    `xml:"category,attr,omitempty"` // Comma-separated slash-delimited paths, e.g. "/graduated/Observability"
+	Outlines    []Outline `xml:"outline"`
+}
+
+// Subscription is a feed outline together with the texts of the folders
+// that contain it, outermost first.
+type Subscription struct {
+	Outline
+	Folders []string
+}
+
+// Parse reads an OPML document.
+func Parse(r io.Reader) (*Document, error) {
+	var doc Document
+	dec := xml.NewDecoder(r)
+	// OPML files in the wild declare ISO-8859-1 and other charsets; the
+	// attributes we read are URLs and titles, so pass bytes through.
+	dec.CharsetReader = func(_ string, input io.Reader) (io.Reader, error) { return input, nil }
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("parse OPML: %w", err)
+	}
+	return &doc, nil
+}
+
+// Subscriptions returns every outline with an xmlUrl, depth first.
+func (d *Document) Subscriptions() []Subscription {
+	var subs []Subscription
+	var walk func(outlines []Outline, folders []string)
+	walk = func(outlines []Outline, folders []string) {
+		for _, o := range outlines {
+			if o.XMLURL != "" {
+				subs = append(subs, Subscription{Outline: o, Folders: folders})
+			}
+			if len(o.Outlines) > 0 {
+				walk(o.Outlines, append(folders[:len(folders):len(folders)], o.Text))
+			}
+		}
+	}
+	walk(d.Body.Outlines, nil)
+	return subs
+}
+
+// Encode writes the document as indented XML.
+func (d *Document) Encode(w io.Writer) error {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", "  ")
+	if err := enc.Encode(d); err != nil {
+		return fmt.Errorf("encode OPML: %w", err)
+	}
+	buf.WriteByte('\n')
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// uncategorized is the folder for feeds without a landscape category.
+const uncategorized = "Other"
+
+// tierTitles orders and names the maturity folders.
+var tierTitles = []struct{ tier, title string }{
+	{"graduated", "Graduated"},
+	{"incubating", "Incubating"},
+	{"sandbox", "Sandbox"},
+}
+
+// Export builds an OPML document from the release feeds and blogs in config,
+// grouped by maturity and then by landscape category. Outline titles are
+// project names and htmlUrl is the project homepage (or blog) when the
+// landscape knows it. landscapeData may be nil.
+func Export(config *models.FeedConfig, landscapeData map[string]models.LandscapeProject, now time.Time) *Document {
+	byName := make(map[string]models.LandscapeProject, len(landscapeData))
+	for _, proj := range landscapeData {
+		byName[proj.Name] = proj
+	}
+
+	// tier → landscape category → subscriptions
+	groups := make(map[string]map[string][]Outline)
+	add := func(tier, category string, o Outline) {
+		if groups[tier] == nil {
+			groups[tier] = make(map[string][]Outline)
+		}
+		if category == "" {
+			category = uncategorized
+		}
+		// "/" and "," separate categories in the attribute (e.g. "CI/CD").
+		o.Category = "/" + tier + "/" + strings.NewReplacer("/", "-", ",", "").Replace(category)
+		groups[tier][category] = append(groups[tier][category], o)
+	}
+
+	for _, f := range config.Feeds {
+		slug := urlutil.ExtractOrgRepo(f.URL)
+		proj, ok := landscapeData[slug]
+		if !ok && f.Project != nil {
+			proj = byName[*f.Project]
+		}
+		name := proj.Name
+		switch {
+		case f.Project != nil && *f.Project != "":
+			name = *f.Project
+		case name == "":
+			name = slug
+		}
+		homepage := proj.HomepageURL
+		if homepage == "" && slug != "" {
+			homepage = "https://github.com/" + slug
+		}
+		add(f.Category, proj.Category, Outline{
+			Text:        name,
+			Title:       name,
+			Type:        "rss",
+			XMLURL:      f.URL,
+			HTMLURL:     homepage,
+			Description: proj.Description,
+		})
+	}
+	for _, b := range config.Blogs {
+		proj := byName[b.Project]
+		htmlURL := b.BlogURL
+		if htmlURL == "" {
+			htmlURL = proj.HomepageURL
+		}
+		title := b.Project + " Blog"
+		add(b.Category, proj.Category, Outline{
+			Text:        title,
+			Title:       title,
+			Type:        "rss",
+			XMLURL:      b.URL,
+			HTMLURL:     htmlURL,
+			Description: proj.Description,
+		})
+	}
+
+	doc := &Document{
+		Version: "2.0",
+		Head: Head{
+			Title:       "CNCF Project Releases and Blogs",
+			DateCreated: now.UTC().Format(time.RFC1123Z),
+			Docs:        "http://opml.org/spec2.opml",
+		},
+	}
+	for _, t := range tierTitles {
+		categories := groups[t.tier]
+		delete(groups, t.tier)
+		if folder, ok := folder(t.title, categories); ok {
+			doc.Body.Outlines = append(doc.Body.Outlines, folder)
+		}
+	}
+	// Categories outside the three tiers (hand-edited entries) go last.
+	var rest []string
+	for tier := range groups {
+		rest = append(rest, tier)
+	}
+	sort.Strings(rest)
+	for _, tier := range rest {
+		if folder, ok := folder(tier, groups[tier]); ok {
+			doc.Body.Outlines = append(doc.Body.Outlines, folder)
+		}
+	}
+	return doc
+}
+
+// folder nests subscriptions under one outline per landscape category, with
+// categories and subscriptions sorted by name.
+func folder(text string, categories map[string][]Outline) (Outline, bool) {
+	if len(categories) == 0 {
+		return Outline{}, false
+	}
+	names := make([]string, 0, len(categories))
+	for name := range categories {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		// Keep "Other" after the real categories.
+		if (names[i] == uncategorized) != (names[j] == uncategorized) {
+			return names[j] == uncategorized
+		}
+		return names[i] < names[j]
+	})
+
+	f := Outline{Text: text, Title: text}
+	for _, name := range names {
+		subs := categories[name]
+		sort.SliceStable(subs, func(i, j int) bool { return strings.ToLower(subs[i].Text) < strings.ToLower(subs[j].Text) })
+		f.Outlines = append(f.Outlines, Outline{Text: name, Title: name, Outlines: subs})
+	}
+	return f, true
+}
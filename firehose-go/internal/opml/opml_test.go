@@ -0,0 +1,83 @@
+package opml
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/castrojo/firehose-go/internal/models"
+)
+
+func TestExport(t *testing.T) {
+	override := "Custom Tool"
+	config := &models.FeedConfig{
+		Feeds: []models.FeedSource{
+			{URL: "https://github.com/prometheus/prometheus/releases.atom", Category: "graduated"},
+			{URL: "https://github.com/kubernetes/kubernetes/releases.atom", Category: "graduated"},
+			{URL: "https://github.com/example/tool/releases.atom", Category: "sandbox", Project: &override},
+		},
+		Blogs: []models.BlogSource{
+			{URL: "https://kubernetes.io/feed.xml", Category: "graduated", Project: "Kubernetes", BlogURL: "https://kubernetes.io/blog/"},
+		},
+	}
+	landscapeData := map[string]models.LandscapeProject{
+		"kubernetes/kubernetes": {Name: "Kubernetes", HomepageURL: "https://kubernetes.io", Category: "Orchestration & Management", Status: "graduated"},
+		"prometheus/prometheus": {Name: "Prometheus", HomepageURL: "https://prometheus.io", Category: "Observability and Analysis", Status: "graduated"},
+	}
+
+	doc := Export(config, landscapeData, time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC))
+
+	if len(doc.Body.Outlines) != 2 || doc.Body.Outlines[0].Text != "Graduated" || doc.Body.Outlines[1].Text != "Sandbox" {
+		t.Fatalf("tier folders = %+v", doc.Body.Outlines)
+	}
+	graduated := doc.Body.Outlines[0].Outlines
+	if len(graduated) != 2 || graduated[0].Text != "Observability and Analysis" || graduated[1].Text != "Orchestration & Management" {
+		t.Fatalf("category folders = %+v", graduated)
+	}
+	k8s := graduated[1].Outlines
+	if len(k8s) != 2 || k8s[0].Text != "Kubernetes" || k8s[1].Text != "Kubernetes Blog" {
+		t.Fatalf("kubernetes outlines = %+v", k8s)
+	}
+	if k8s[0].HTMLURL != "https://kubernetes.io" || k8s[0].Type != "rss" || k8s[0].Category != "/graduated/Orchestration & Management" {
+		t.Errorf("kubernetes release outline = %+v", k8s[0])
+	}
+	if k8s[1].HTMLURL != "https://kubernetes.io/blog/" {
+		t.Errorf("blog htmlUrl = %q", k8s[1].HTMLURL)
+	}
+	tool := doc.Body.Outlines[1].Outlines[0]
+	if tool.Text != "Other" || tool.Outlines[0].Text != "Custom Tool" || tool.Outlines[0].HTMLURL != "https://github.com/example/tool" {
+		t.Errorf("unmatched feed = %+v", tool)
+	}
+	if doc.Head.DateCreated != "Sun, 01 Feb 2026 00:00:00 +0000" {
+		t.Errorf("dateCreated = %q", doc.Head.DateCreated)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	config := &models.FeedConfig{
+		Feeds: []models.FeedSource{{URL: "https://github.com/a/b/releases.atom?x=1&y=2", Category: "incubating"}},
+	}
+	var buf bytes.Buffer
+	if err := Export(config, nil, time.Now()).Encode(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(buf.String(), `<?xml version="1.0" encoding="UTF-8"?>`) || !strings.Contains(buf.String(), `<opml version="2.0">`) {
+		t.Errorf("unexpected document:\n%s", buf.String())
+	}
+
+	doc, err := Parse(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	subs := doc.Subscriptions()
+	if len(subs) != 1 {
+		t.Fatalf("subscriptions = %+v", subs)
+	}
+	if subs[0].XMLURL != "https://github.com/a/b/releases.atom?x=1&y=2" || subs[0].Text != "a/b" {
+		t.Errorf("subscription = %+v", subs[0])
+	}
+	if strings.Join(subs[0].Folders, "/") != "Incubating/Other" {
+		t.Errorf("folders = %v", subs[0].Folders)
+	}
+}
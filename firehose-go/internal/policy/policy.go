@@ -0,0 +1,158 @@
+// Package policy decides whether a pipeline run is good enough to publish.
+//
+// Thresholds are set per feed kind (release/blog) and per landscape category in
+// the policy section of feeds.yaml; feeds on the must-succeed list fail the run
+// on their own. Quarantined feeds are not fetched and do not count.
+package policy
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/castrojo/firehose-go/internal/models"
+)
+
+// DefaultReleaseMinSuccessRate applies when the config sets no release threshold.
+const DefaultReleaseMinSuccessRate = 0.5
+
+// Exit statuses for a breached policy. When several policies are breached the
+// process exits with the highest one.
+const (
+	ExitKindThreshold     = 2
+	ExitCategoryThreshold = 3
+	ExitMustSucceed       = 4
+)
+
+// Breach describes one policy the run did not meet.
+type Breach struct {
+	Policy    string   `json:"policy"` // e.g. "release.min_success_rate", "blog.categories.graduated", "must_succeed"
+	Detail    string   `json:"detail"`
+	Threshold float64  `json:"threshold,omitempty"`
+	Actual    float64  `json:"actual,omitempty"`
+	Feeds     []string `json:"feeds,omitempty"` // failed feeds behind the breach, for must_succeed
+	ExitCode  int      `json:"exitCode"`
+}
+
+// Evaluate checks the fetch results against the config's policy.
+func Evaluate(config *models.FeedConfig, releaseStatuses, blogStatuses []models.FeedStatus) []Breach {
+	p := config.Policy
+	release := p.Release
+	if release.MinSuccessRate == nil {
+		rate := DefaultReleaseMinSuccessRate
+		release.MinSuccessRate = &rate
+	}
+
+	releaseCategories := make(map[string]string, len(config.Feeds))
+	for _, f := range config.Feeds {
+		releaseCategories[f.URL] = f.Category
+	}
+	blogCategories := make(map[string]string, len(config.Blogs))
+	for _, b := range config.Blogs {
+		blogCategories[b.URL] = b.Category
+	}
+
+	breaches := []Breach{} // never nil, so the summary JSON shows an empty list
+	breaches = append(breaches, evaluateKind("release", release, releaseStatuses, releaseCategories)...)
+	breaches = append(breaches, evaluateKind("blog", p.Blog, blogStatuses, blogCategories)...)
+	if b, ok := evaluateMustSucceed(p.MustSucceed, append(releaseStatuses, blogStatuses...)); ok {
+		breaches = append(breaches, b)
+	}
+	return breaches
+}
+
+// ExitCode returns the process exit status for breaches, or 0 when there are none.
+func ExitCode(breaches []Breach) int {
+	code := 0
+	for _, b := range breaches {
+		code = max(code, b.ExitCode)
+	}
+	return code
+}
+
+// evaluateKind checks the overall and per-category success rates of one feed kind.
+func evaluateKind(kind string, p models.KindPolicy, statuses []models.FeedStatus, categories map[string]string) []Breach {
+	var breaches []Breach
+
+	if p.MinSuccessRate != nil {
+		if rate, ok, total := successRate(statuses, nil); ok && rate < *p.MinSuccessRate {
+			breaches = append(breaches, Breach{
+				Policy:    kind + ".min_success_rate",
+				Detail:    fmt.Sprintf("%.1f%% of %d %s feeds succeeded (threshold %.1f%%)", rate*100, total, kind, *p.MinSuccessRate*100),
+				Threshold: *p.MinSuccessRate,
+				Actual:    rate,
+				ExitCode:  ExitKindThreshold,
+			})
+		}
+	}
+
+	names := make([]string, 0, len(p.Categories))
+	for category := range p.Categories {
+		names = append(names, category)
+	}
+	sort.Strings(names)
+	for _, category := range names {
+		threshold := p.Categories[category]
+		inCategory := func(feedURL string) bool { return categories[feedURL] == category }
+		if rate, ok, total := successRate(statuses, inCategory); ok && rate < threshold {
+			breaches = append(breaches, Breach{
+				Policy: kind + ".categories." + category,
+				Detail: fmt.Sprintf("%.1f%% of %d %s %s feeds succeeded (threshold %.1f%%)",
+					rate*100, total, category, kind, threshold*100),
+				Threshold: threshold,
+				Actual:    rate,
+				ExitCode:  ExitCategoryThreshold,
+			})
+		}
+	}
+	return breaches
+}
+
+// successRate returns the share of matching feeds that succeeded. ok is false
+// when no feed matched, so an empty kind or category never breaches.
+func successRate(statuses []models.FeedStatus, match func(feedURL string) bool) (rate float64, ok bool, total int) {
+	succeeded := 0
+	for _, s := range statuses {
+		if match != nil && !match(s.FeedURL) {
+			continue
+		}
+		total++
+		if s.Status == "success" {
+			succeeded++
+		}
+	}
+	if total == 0 {
+		return 0, false, 0
+	}
+	return float64(succeeded) / float64(total), true, total
+}
+
+// evaluateMustSucceed fails the run when a must-succeed feed failed or was not
+// fetched. Must-succeed feeds are exempt from quarantine, so a feed that was
+// not fetched is missing from feeds.yaml or failed validation.
+func evaluateMustSucceed(mustSucceed []string, statuses []models.FeedStatus) (Breach, bool) {
+	byURL := make(map[string]models.FeedStatus, len(statuses))
+	for _, s := range statuses {
+		byURL[s.FeedURL] = s
+	}
+
+	var failed, reasons []string
+	for _, feedURL := range mustSucceed {
+		s, fetched := byURL[feedURL]
+		switch {
+		case !fetched:
+			failed = append(failed, feedURL)
+			reasons = append(reasons, feedURL+": not fetched (not configured or invalid)")
+		case s.Status != "success":
+			failed = append(failed, feedURL)
+			reasons = append(reasons, feedURL+": "+s.Error)
+		}
+	}
+	if len(failed) == 0 {
+		return Breach{}, false
+	}
+	detail := fmt.Sprintf("%d of %d must-succeed feeds failed", len(failed), len(mustSucceed))
+	for _, r := range reasons {
+		detail += "; " + r
+	}
+	return Breach{Policy: "must_succeed", Detail: detail, Feeds: failed, ExitCode: ExitMustSucceed}, true
+}
@@ -0,0 +1,146 @@
+package policy
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/castrojo/firehose-go/internal/models"
+)
+
+func statuses(prefix string, ok, failed int) []models.FeedStatus {
+	var out []models.FeedStatus
+	for i := 0; i < ok+failed; i++ {
+		status := models.FeedStatus{FeedURL: fmt.Sprintf("https://%s.example/%d", prefix, i), Status: "success"}
+		if i >= ok {
+			status.Status = "error"
+			status.Error = "HTTP 500"
+		}
+		out = append(out, status)
+	}
+	return out
+}
+
+func sources(list []models.FeedStatus, category string) []models.FeedSource {
+	var out []models.FeedSource
+	for _, s := range list {
+		out = append(out, models.FeedSource{URL: s.FeedURL, Category: category})
+	}
+	return out
+}
+
+func rate(f float64) *float64 { return &f }
+
+func TestEvaluate(t *testing.T) {
+	graduated := statuses("graduated", 1, 3) // 25% success
+	sandbox := statuses("sandbox", 9, 1)     // 90% success
+	releases := append(append([]models.FeedStatus{}, graduated...), sandbox...)
+	blogs := statuses("blog", 1, 4) // 20% success
+
+	config := func(p models.PolicyConfig) *models.FeedConfig {
+		return &models.FeedConfig{
+			Policy: p,
+			Feeds:  append(sources(graduated, "graduated"), sources(sandbox, "sandbox")...),
+			Blogs: []models.BlogSource{
+				{URL: blogs[0].FeedURL, Category: "graduated"},
+			},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		policy   models.PolicyConfig
+		releases []models.FeedStatus
+		want     []string // breached policies
+		exit     int
+	}{
+		{
+			name:     "default release threshold passes at 71%",
+			releases: releases,
+			want:     nil,
+		},
+		{
+			name:     "default release threshold fails below 50%",
+			releases: graduated,
+			want:     []string{"release.min_success_rate"},
+			exit:     ExitKindThreshold,
+		},
+		{
+			name:     "category threshold catches losing graduated projects",
+			policy:   models.PolicyConfig{Release: models.KindPolicy{Categories: map[string]float64{"graduated": 0.9, "sandbox": 0.5}}},
+			releases: releases,
+			want:     []string{"release.categories.graduated"},
+			exit:     ExitCategoryThreshold,
+		},
+		{
+			name:     "blog failures count when configured",
+			policy:   models.PolicyConfig{Blog: models.KindPolicy{MinSuccessRate: rate(0.5)}},
+			releases: releases,
+			want:     []string{"blog.min_success_rate"},
+			exit:     ExitKindThreshold,
+		},
+		{
+			name:     "must-succeed feed fails on its own",
+			policy:   models.PolicyConfig{MustSucceed: []string{graduated[3].FeedURL, sandbox[0].FeedURL}},
+			releases: releases,
+			want:     []string{"must_succeed"},
+			exit:     ExitMustSucceed,
+		},
+		{
+			name:     "unfetched must-succeed feed fails",
+			policy:   models.PolicyConfig{MustSucceed: []string{"https://quarantined.example/feed"}},
+			releases: releases,
+			want:     []string{"must_succeed"},
+			exit:     ExitMustSucceed,
+		},
+		{
+			name: "highest exit status wins",
+			policy: models.PolicyConfig{
+				Release:     models.KindPolicy{MinSuccessRate: rate(0.8), Categories: map[string]float64{"graduated": 0.5}},
+				MustSucceed: []string{graduated[2].FeedURL},
+			},
+			releases: releases,
+			want:     []string{"release.min_success_rate", "release.categories.graduated", "must_succeed"},
+			exit:     ExitMustSucceed,
+		},
+		{
+			name:     "release threshold can be disabled",
+			policy:   models.PolicyConfig{Release: models.KindPolicy{MinSuccessRate: rate(0)}},
+			releases: graduated,
+			want:     nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			breaches := Evaluate(config(tt.policy), tt.releases, blogs)
+			var got []string
+			for _, b := range breaches {
+				got = append(got, b.Policy)
+				if b.Detail == "" {
+					t.Errorf("%s breach has no detail", b.Policy)
+				}
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("breached %v, want %v", got, tt.want)
+			}
+			if code := ExitCode(breaches); code != tt.exit {
+				t.Errorf("ExitCode() = %d, want %d", code, tt.exit)
+			}
+		})
+	}
+}
+
+func TestMustSucceedDetail(t *testing.T) {
+	failed := statuses("k8s", 0, 1)
+	breaches := Evaluate(&models.FeedConfig{
+		Policy: models.PolicyConfig{Release: models.KindPolicy{MinSuccessRate: rate(0)}, MustSucceed: []string{failed[0].FeedURL}},
+	}, failed, nil)
+	if len(breaches) != 1 {
+		t.Fatalf("Evaluate() = %+v, want one breach", breaches)
+	}
+	b := breaches[0]
+	if len(b.Feeds) != 1 || b.Feeds[0] != failed[0].FeedURL || !strings.Contains(b.Detail, "HTTP 500") {
+		t.Errorf("breach = %+v, want the failed feed and its error", b)
+	}
+}
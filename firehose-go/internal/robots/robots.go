@@ -0,0 +1,396 @@
+// Package robots implements robots.txt parsing and crawl politeness for outgoing requests.
+//
+// A Checker fetches and caches robots.txt once per host, refuses requests to
+// disallowed paths, and spaces requests to a host according to its Crawl-delay.
+// Use Checker.Transport to apply it to an http.Client.
+package robots
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/castrojo/firehose-go/internal/metrics"
+)
+
+// DefaultUserAgent identifies the pipeline to the sites it crawls.
+const DefaultUserAgent = "firehose-go/1.0 (+https://github.com/castrojo/firehose)"
+
+// maxCrawlDelay caps the Crawl-delay honoured for one host, so a misconfigured
+// robots.txt cannot stall a run indefinitely.
+const maxCrawlDelay = 60 * time.Second
+
+// Default is the process-wide checker shared by feed fetching and blog discovery.
+// Its requests are recorded by metrics.Default.
+var Default = NewChecker(DefaultUserAgent, metrics.Default.Transport(nil))
+
+// SetUserAgent changes the User-Agent of the Default checker. Empty values are ignored.
+func SetUserAgent(userAgent string) {
+	Default.SetUserAgent(userAgent)
+}
+
+// DisallowedError is returned for requests that robots.txt does not allow.
+type DisallowedError struct {
+	URL    string
+	Reason string
+}
+
+func (e *DisallowedError) Error() string {
+	return fmt.Sprintf("robots.txt disallows %s (%s)", e.URL, e.Reason)
+}
+
+// IsDisallowed reports whether err was caused by a robots.txt disallow rule.
+func IsDisallowed(err error) bool {
+	var d *DisallowedError
+	return errors.As(err, &d)
+}
+
+// Checker enforces robots.txt rules per host.
+type Checker struct {
+	base http.RoundTripper
+
+	mu        sync.Mutex
+	userAgent string
+	hosts     map[string]*hostState
+}
+
+// hostState holds the cached robots.txt rules and request pacing for one host.
+type hostState struct {
+	ready chan struct{} // closed once rules are loaded
+	rules *rules
+
+	mu   sync.Mutex
+	next time.Time // earliest time the next request may start
+}
+
+// NewChecker returns a checker using base to fetch robots.txt and to send
+// allowed requests. A nil base uses http.DefaultTransport.
+func NewChecker(userAgent string, base http.RoundTripper) *Checker {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &Checker{base: base, userAgent: userAgent, hosts: make(map[string]*hostState)}
+}
+
+// UserAgent returns the User-Agent sent with every request.
+func (c *Checker) UserAgent() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.userAgent
+}
+
+// SetUserAgent changes the User-Agent and drops cached rules, since a different
+// agent may match a different robots.txt group. Empty values are ignored.
+func (c *Checker) SetUserAgent(userAgent string) {
+	if userAgent == "" {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.userAgent != userAgent {
+		c.userAgent = userAgent
+		c.hosts = make(map[string]*hostState)
+	}
+}
+
+// Allowed reports whether rawURL may be fetched, with a reason when it may not.
+func (c *Checker) Allowed(rawURL string) (bool, string) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return true, ""
+	}
+	r := c.host(u).rules
+	return r.allowed(pathAndQuery(u))
+}
+
+// Transport returns a RoundTripper that sets the User-Agent, rejects requests
+// disallowed by robots.txt and waits out each host's Crawl-delay.
+//
+// timeout bounds each request from the moment it is sent until its body is
+// closed, like http.Client.Timeout, but it starts only after the robots.txt
+// lookup and the Crawl-delay wait. Clients using this transport should leave
+// their own Timeout unset, or a long delay would count against it. Zero means
+// no limit.
+func (c *Checker) Transport(timeout time.Duration) http.RoundTripper {
+	return roundTripper{c: c, timeout: timeout}
+}
+
+type roundTripper struct {
+	c       *Checker
+	timeout time.Duration
+}
+
+func (rt roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	h := rt.c.host(req.URL)
+	if ok, reason := h.rules.allowed(pathAndQuery(req.URL)); !ok {
+		return nil, &DisallowedError{URL: req.URL.String(), Reason: reason}
+	}
+	if err := h.wait(req, h.rules.crawlDelay); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := req.Context(), context.CancelFunc(func() {})
+	if rt.timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, rt.timeout)
+	}
+	req = req.Clone(ctx)
+	req.Header.Set("User-Agent", rt.c.UserAgent())
+	resp, err := rt.c.base.RoundTrip(req)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelBody releases a request's timeout once its body is closed.
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// host returns the state for u's host, fetching robots.txt on first use.
+// Concurrent callers for the same host wait for the single fetch.
+func (c *Checker) host(u *url.URL) *hostState {
+	key := u.Scheme + "://" + u.Host
+	c.mu.Lock()
+	h, ok := c.hosts[key]
+	if !ok {
+		h = &hostState{ready: make(chan struct{})}
+		c.hosts[key] = h
+	}
+	userAgent := c.userAgent
+	c.mu.Unlock()
+
+	if !ok {
+		h.rules = c.fetch(key, userAgent)
+		close(h.ready)
+	}
+	<-h.ready
+	return h
+}
+
+// fetch downloads and parses robots.txt for origin. Following RFC 9309, a 4xx
+// response allows everything. Server errors and network failures are also
+// treated as allow-all so an unreachable robots.txt does not block a run.
+func (c *Checker) fetch(origin, userAgent string) *rules {
+	robotsURL := origin + "/robots.txt"
+	req, err := http.NewRequest(http.MethodGet, robotsURL, nil)
+	if err != nil {
+		return &rules{}
+	}
+	req.Header.Set("User-Agent", userAgent)
+	client := &http.Client{Transport: c.base, Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		log.Printf("  robots.txt: %s unreachable, allowing all: %v", robotsURL, err)
+		return &rules{}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		if resp.StatusCode >= 500 {
+			log.Printf("  robots.txt: %s returned HTTP %d, allowing all", robotsURL, resp.StatusCode)
+		}
+		return &rules{}
+	}
+	return parse(io.LimitReader(resp.Body, 512*1024), userAgent)
+}
+
+// wait blocks until the host's crawl delay since the previous request has passed.
+// A request cancelled while waiting gives its slot back, unless later requests
+// have already been scheduled after it.
+func (h *hostState) wait(req *http.Request, delay time.Duration) error {
+	if delay <= 0 {
+		return nil
+	}
+	h.mu.Lock()
+	now := time.Now()
+	start := now
+	if h.next.After(now) {
+		start = h.next
+	}
+	h.next = start.Add(delay)
+	reserved := h.next
+	h.mu.Unlock()
+
+	if d := start.Sub(now); d > 0 {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			h.mu.Lock()
+			if h.next.Equal(reserved) {
+				h.next = start
+			}
+			h.mu.Unlock()
+			return req.Context().Err()
+		}
+	}
+	return nil
+}
+
+// rules are the robots.txt directives that apply to our user agent.
+type rules struct {
+	allow      []string
+	disallow   []string
+	crawlDelay time.Duration
+}
+
+// allowed applies the longest-match rule; Allow wins ties (RFC 9309 §2.2.2).
+func (r *rules) allowed(path string) (bool, string) {
+	if path == "/robots.txt" {
+		return true, ""
+	}
+	bestAllow, bestDisallow := -1, -1
+	var disallowRule string
+	for _, p := range r.allow {
+		if matches(p, path) && len(p) > bestAllow {
+			bestAllow = len(p)
+		}
+	}
+	for _, p := range r.disallow {
+		if matches(p, path) && len(p) > bestDisallow {
+			bestDisallow = len(p)
+			disallowRule = p
+		}
+	}
+	if bestDisallow > bestAllow {
+		return false, "Disallow: " + disallowRule
+	}
+	return true, ""
+}
+
+// matches reports whether a robots.txt path pattern matches path.
+// Patterns support "*" wildcards and a trailing "$" end anchor.
+func matches(pattern, path string) bool {
+	anchored := strings.HasSuffix(pattern, "$")
+	parts := strings.Split(strings.TrimSuffix(pattern, "$"), "*")
+
+	if !strings.HasPrefix(path, parts[0]) {
+		return false
+	}
+	rest := path[len(parts[0]):]
+	if len(parts) == 1 {
+		return !anchored || rest == ""
+	}
+	// Leftmost matches of the middle segments leave the most room for the last one.
+	for _, part := range parts[1 : len(parts)-1] {
+		idx := strings.Index(rest, part)
+		if idx < 0 {
+			return false
+		}
+		rest = rest[idx+len(part):]
+	}
+	last := parts[len(parts)-1]
+	if anchored {
+		return strings.HasSuffix(rest, last)
+	}
+	return strings.Contains(rest, last)
+}
+
+// parse reads robots.txt and returns the group for userAgent, falling back to "*".
+// A group applies when its User-agent token equals our product token (the part
+// of the User-Agent before "/"), compared case-insensitively.
+func parse(r io.Reader, userAgent string) *rules {
+	product := strings.ToLower(strings.SplitN(userAgent, "/", 2)[0])
+
+	var specific, wildcard *rules
+	var current []*rules // groups the current rule lines apply to
+	inAgents := false
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent":
+			if !inAgents {
+				current = nil
+				inAgents = true
+			}
+			agent := strings.ToLower(value)
+			switch {
+			case agent == "*":
+				if wildcard == nil {
+					wildcard = &rules{}
+				}
+				current = append(current, wildcard)
+			case agent == product:
+				if specific == nil {
+					specific = &rules{}
+				}
+				current = append(current, specific)
+			}
+		case "allow", "disallow", "crawl-delay":
+			inAgents = false
+			for _, g := range current {
+				switch key {
+				case "allow":
+					if value != "" {
+						g.allow = append(g.allow, value)
+					}
+				case "disallow":
+					// An empty Disallow allows everything; it adds no rule.
+					if value != "" {
+						g.disallow = append(g.disallow, value)
+					}
+				case "crawl-delay":
+					if secs, err := strconv.ParseFloat(value, 64); err == nil && secs > 0 {
+						g.crawlDelay = min(time.Duration(secs*float64(time.Second)), maxCrawlDelay)
+					}
+				}
+			}
+		default:
+			// Sitemap and unknown directives end a User-agent run but do not reset the group.
+			inAgents = false
+		}
+	}
+
+	switch {
+	case specific != nil:
+		return specific
+	case wildcard != nil:
+		return wildcard
+	default:
+		return &rules{}
+	}
+}
+
+// pathAndQuery returns the URL path (defaulting to "/") with its query string.
+func pathAndQuery(u *url.URL) string {
+	p := u.EscapedPath()
+	if p == "" {
+		p = "/"
+	}
+	if u.RawQuery != "" {
+		p += "?" + u.RawQuery
+	}
+	return p
+}
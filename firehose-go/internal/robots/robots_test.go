@@ -0,0 +1,202 @@
+package robots
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	const robotsTxt = `# Example robots.txt
+User-agent: *
+Disallow: /private/
+Crawl-delay: 2
+
+User-agent: Googlebot
+User-agent: firehose-go
+Disallow: /search
+Allow: /private/feed.xml
+Crawl-delay: 500
+
+Sitemap: https://example.com/sitemap.xml
+`
+	t.Run("specific group wins over wildcard", func(t *testing.T) {
+		r := parse(strings.NewReader(robotsTxt), DefaultUserAgent)
+		if len(r.disallow) != 1 || r.disallow[0] != "/search" {
+			t.Errorf("disallow = %v, want [/search]", r.disallow)
+		}
+		if r.crawlDelay != maxCrawlDelay {
+			t.Errorf("crawlDelay = %v, want capped at %v", r.crawlDelay, maxCrawlDelay)
+		}
+	})
+
+	t.Run("falls back to wildcard group", func(t *testing.T) {
+		r := parse(strings.NewReader(robotsTxt), "other-bot/2.0")
+		if len(r.disallow) != 1 || r.disallow[0] != "/private/" {
+			t.Errorf("disallow = %v, want [/private/]", r.disallow)
+		}
+		if r.crawlDelay != 2*time.Second {
+			t.Errorf("crawlDelay = %v, want 2s", r.crawlDelay)
+		}
+	})
+
+	t.Run("no matching group allows everything", func(t *testing.T) {
+		r := parse(strings.NewReader("User-agent: Googlebot\nDisallow: /\n"), DefaultUserAgent)
+		if ok, _ := r.allowed("/anything"); !ok {
+			t.Error("path disallowed without a matching group")
+		}
+	})
+}
+
+func TestAllowed(t *testing.T) {
+	r := &rules{
+		allow:    []string{"/blog/feed", "/docs/*.xml$"},
+		disallow: []string{"/blog", "/docs/", "/*.json$", "/tmp*cache"},
+	}
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"/", true},
+		{"/blog", false},
+		{"/blog/post-1", false},
+		{"/blog/feed", true},      // longer Allow wins
+		{"/blog/feed.xml", true},  // prefix match
+		{"/docs/index.xml", true}, // wildcard Allow is longer than /docs/
+		{"/docs/index.xml?x", false},
+		{"/data/items.json", false},
+		{"/data/items.json?page=2", true}, // $ anchors the end of the path
+		{"/tmp/a/cache/b", false},
+		{"/tmp/a/b", true},
+		{"/robots.txt", true},
+	}
+	for _, tt := range tests {
+		if got, _ := r.allowed(tt.path); got != tt.want {
+			t.Errorf("allowed(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestTransport(t *testing.T) {
+	var robotsFetches atomic.Int32
+	var gotUserAgent atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			robotsFetches.Add(1)
+			fmt.Fprint(w, "User-agent: *\nDisallow: /private/\n")
+			return
+		}
+		gotUserAgent.Store(r.Header.Get("User-Agent"))
+		fmt.Fprint(w, "ok")
+	}))
+	defer server.Close()
+
+	checker := NewChecker("test-agent/1.0", nil)
+	client := &http.Client{Transport: checker.Transport(0)}
+
+	resp, err := client.Get(server.URL + "/feed.xml")
+	if err != nil {
+		t.Fatalf("allowed request failed: %v", err)
+	}
+	resp.Body.Close()
+	if got := gotUserAgent.Load(); got != "test-agent/1.0" {
+		t.Errorf("User-Agent = %v, want test-agent/1.0", got)
+	}
+
+	_, err = client.Get(server.URL + "/private/feed.xml")
+	if !IsDisallowed(err) {
+		t.Errorf("disallowed request error = %v, want DisallowedError", err)
+	}
+	if ok, reason := checker.Allowed(server.URL + "/private/x"); ok || reason != "Disallow: /private/" {
+		t.Errorf("Allowed() = %v, %q", ok, reason)
+	}
+	if n := robotsFetches.Load(); n != 1 {
+		t.Errorf("robots.txt fetched %d times, want 1", n)
+	}
+}
+
+func TestTransportMissingRobots(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, "ok")
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewChecker(DefaultUserAgent, nil).Transport(0)}
+	resp, err := client.Get(server.URL + "/anything")
+	if err != nil {
+		t.Fatalf("request failed without robots.txt: %v", err)
+	}
+	resp.Body.Close()
+}
+
+func TestCrawlDelay(t *testing.T) {
+	h := &hostState{}
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/", nil)
+	delay := 50 * time.Millisecond
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := h.wait(req, delay); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The first request is immediate; the next two each wait one delay.
+	if elapsed := time.Since(start); elapsed < 2*delay {
+		t.Errorf("3 requests took %v, want at least %v", elapsed, 2*delay)
+	}
+}
+
+func TestCrawlDelayOutsideTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			fmt.Fprint(w, "User-agent: *\nCrawl-delay: 0.2\n")
+			return
+		}
+		fmt.Fprint(w, "ok")
+	}))
+	defer server.Close()
+
+	// The second and third requests wait 200ms and 400ms for their slots, longer
+	// than the timeout; only the exchange itself should count against it.
+	client := &http.Client{Transport: NewChecker(DefaultUserAgent, nil).Transport(100 * time.Millisecond)}
+	for i := 0; i < 3; i++ {
+		resp, err := client.Get(server.URL + "/feed.xml")
+		if err != nil {
+			t.Fatalf("request %d: %v", i, err)
+		}
+		if _, err := io.ReadAll(resp.Body); err != nil {
+			t.Fatalf("request %d: read body: %v", i, err)
+		}
+		resp.Body.Close()
+	}
+}
+
+func TestCancelledWaitReleasesSlot(t *testing.T) {
+	h := &hostState{}
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/", nil)
+	delay := time.Hour
+
+	if err := h.wait(req, delay); err != nil { // Takes the first slot immediately
+		t.Fatal(err)
+	}
+	next := h.next
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := h.wait(req.WithContext(ctx), delay); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("wait() = %v, want deadline exceeded", err)
+	}
+	if !h.next.Equal(next) {
+		t.Errorf("next = %v after cancelled wait, want %v (slot given back)", h.next, next)
+	}
+}
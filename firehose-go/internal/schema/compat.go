@@ -0,0 +1,191 @@
+package schema
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Change is one difference between two versions of the schema.
+type Change struct {
+	Path     string // JSON path of the property, e.g. "releases[].feedUrl"
+	Detail   string
+	Breaking bool // Consumers written against the old schema may fail on the new one
+}
+
+func (c Change) String() string {
+	kind := "compatible"
+	if c.Breaking {
+		kind = "breaking"
+	}
+	return fmt.Sprintf("%s: %s (%s)", c.Path, c.Detail, kind)
+}
+
+// Compare lists the differences from old to new, judged from the point of
+// view of a consumer of the output. Removed properties, changed types or
+// formats, properties that are no longer required and new enum values are
+// breaking; added properties, newly required properties and dropped enum
+// values are not.
+func Compare(old, new *Schema) ([]Change, error) {
+	c := &comparer{old: old, new: new}
+	if err := c.compare("$", old, new); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(c.changes, func(i, j int) bool { return c.changes[i].Path < c.changes[j].Path })
+	return c.changes, nil
+}
+
+type comparer struct {
+	old, new *Schema
+	changes  []Change
+}
+
+func (c *comparer) add(path string, breaking bool, format string, args ...any) {
+	c.changes = append(c.changes, Change{Path: path, Detail: fmt.Sprintf(format, args...), Breaking: breaking})
+}
+
+func (c *comparer) compare(path string, old, new *Schema) error {
+	old, err := resolve(c.old, old)
+	if err != nil {
+		return fmt.Errorf("old schema at %s: %w", path, err)
+	}
+	new, err = resolve(c.new, new)
+	if err != nil {
+		return fmt.Errorf("new schema at %s: %w", path, err)
+	}
+
+	if ot, nt := typeString(old.Type), typeString(new.Type); ot != nt {
+		c.add(path, true, "type changed from %s to %s", ot, nt)
+		return nil
+	}
+	if old.Format != new.Format {
+		c.add(path, true, "format changed from %q to %q", old.Format, new.Format)
+	}
+	if len(old.Enum) > 0 || len(new.Enum) > 0 {
+		for _, v := range new.Enum {
+			if len(old.Enum) == 0 || !slices.Contains(old.Enum, v) {
+				c.add(path, true, "enum value %q added", v)
+			}
+		}
+		for _, v := range old.Enum {
+			if !slices.Contains(new.Enum, v) {
+				c.add(path, false, "enum value %q removed", v)
+			}
+		}
+	}
+
+	if old.Items != nil && new.Items != nil {
+		if err := c.compare(path+"[]", old.Items, new.Items); err != nil {
+			return err
+		}
+	}
+	if old.AdditionalProperties != nil && new.AdditionalProperties != nil {
+		if err := c.compare(path+"{}", old.AdditionalProperties, new.AdditionalProperties); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range sortedKeys(old.Properties) {
+		prop := path + "." + name
+		newProp, ok := new.Properties[name]
+		if !ok {
+			c.add(prop, true, "property removed")
+			continue
+		}
+		wasRequired, isRequired := slices.Contains(old.Required, name), slices.Contains(new.Required, name)
+		switch {
+		case wasRequired && !isRequired:
+			c.add(prop, true, "no longer required")
+		case !wasRequired && isRequired:
+			c.add(prop, false, "now required")
+		}
+		if err := c.compare(prop, old.Properties[name], newProp); err != nil {
+			return err
+		}
+	}
+	for _, name := range sortedKeys(new.Properties) {
+		if _, ok := old.Properties[name]; !ok {
+			c.add(path+"."+name, false, "property added")
+		}
+	}
+	return nil
+}
+
+// typeString normalizes the "type" keyword, which is either a name or a list
+// of names depending on whether the schema was generated or decoded.
+func typeString(t any) string {
+	switch t := t.(type) {
+	case nil:
+		return "any"
+	case string:
+		return t
+	case []string:
+		return strings.Join(t, "|")
+	case []any:
+		names := make([]string, len(t))
+		for i, v := range t {
+			names[i] = fmt.Sprint(v)
+		}
+		return strings.Join(names, "|")
+	default:
+		return fmt.Sprint(t)
+	}
+}
+
+func sortedKeys(m map[string]*Schema) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Check compares the published schema old with new and returns an error when
+// new changes the contract without the version bump it needs: a major bump
+// for breaking changes, and any bump for compatible ones.
+func Check(old, new *Schema) ([]Change, error) {
+	changes, err := Compare(old, new)
+	if err != nil {
+		return nil, err
+	}
+	if len(changes) == 0 {
+		return nil, nil
+	}
+
+	oldMajor, err := major(old.Version)
+	if err != nil {
+		return changes, fmt.Errorf("published schema: %w", err)
+	}
+	newMajor, err := major(new.Version)
+	if err != nil {
+		return changes, fmt.Errorf("current schema: %w", err)
+	}
+
+	breaking := 0
+	for _, ch := range changes {
+		if ch.Breaking {
+			breaking++
+		}
+	}
+	switch {
+	case breaking > 0 && newMajor <= oldMajor:
+		return changes, fmt.Errorf("%d breaking schema change(s) require a major version bump (published %s, current %s)",
+			breaking, old.Version, new.Version)
+	case old.Version == new.Version:
+		return changes, fmt.Errorf("schema changed without a version bump (still %s)", new.Version)
+	}
+	return changes, nil
+}
+
+// major returns the major component of a semantic version.
+func major(version string) (int, error) {
+	head, _, _ := strings.Cut(version, ".")
+	n, err := strconv.Atoi(head)
+	if err != nil {
+		return 0, fmt.Errorf("invalid schema version %q", version)
+	}
+	return n, nil
+}
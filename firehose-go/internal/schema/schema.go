@@ -0,0 +1,199 @@
+// Package schema generates the JSON Schema for the pipeline output from the
+// models structs and checks changes to it for compatibility.
+//
+// The models are the source of truth: property names come from json tags,
+// required properties are those without omitempty/omitzero, and validate tags
+// become formats (url → uri) and enums (oneof=…).
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/castrojo/firehose-go/internal/atomicfile"
+	"github.com/castrojo/firehose-go/internal/models"
+)
+
+// Draft is the JSON Schema dialect of generated documents.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is the subset of JSON Schema the generator emits.
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	ID                   string             `json:"$id,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Version              string             `json:"version,omitempty"` // output contract version (models.SchemaVersion)
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 any                `json:"type,omitempty"` // a type name, or a list of them when null is allowed
+	Format               string             `json:"format,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Defs                 map[string]*Schema `json:"$defs,omitempty"`
+}
+
+// Generate returns the schema for models.OutputData.
+func Generate() *Schema {
+	g := &generator{defs: make(map[string]*Schema)}
+	root := g.structSchema(reflect.TypeOf(models.OutputData{}))
+	root.Schema = Draft
+	root.Title = "Firehose releases.json"
+	root.Version = models.SchemaVersion
+	root.Defs = g.defs
+	return root
+}
+
+type generator struct {
+	defs map[string]*Schema
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// typeSchema returns the schema for t. Named structs other than time.Time are
+// emitted once under $defs and referenced.
+func (g *generator) typeSchema(t reflect.Type) *Schema {
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t.Kind() == reflect.Pointer:
+		return g.typeSchema(t.Elem())
+	case t.Kind() == reflect.Struct:
+		if _, ok := g.defs[t.Name()]; !ok {
+			g.defs[t.Name()] = nil // reserve against recursion
+			g.defs[t.Name()] = g.structSchema(t)
+		}
+		return &Schema{Ref: "#/$defs/" + t.Name()}
+	case t.Kind() == reflect.Slice || t.Kind() == reflect.Array:
+		return &Schema{Type: "array", Items: g.typeSchema(t.Elem())}
+	case t.Kind() == reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.typeSchema(t.Elem())}
+	case t.Kind() == reflect.String:
+		return &Schema{Type: "string"}
+	case t.Kind() == reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case t.Kind() >= reflect.Int && t.Kind() <= reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64:
+		return &Schema{Type: "number"}
+	default:
+		return &Schema{}
+	}
+}
+
+func (g *generator) structSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	for _, f := range Fields(t) {
+		prop := g.typeSchema(f.Type)
+		if f.Format != "" {
+			prop.Format = f.Format
+		}
+		if len(f.Enum) > 0 {
+			prop.Enum = f.Enum
+		}
+		// encoding/json writes nil slices and maps as null.
+		if !f.Optional && (f.Type.Kind() == reflect.Slice || f.Type.Kind() == reflect.Map) {
+			prop.Type = []string{prop.Type.(string), "null"}
+		}
+		s.Properties[f.Name] = prop
+		if !f.Optional {
+			s.Required = append(s.Required, f.Name)
+		}
+	}
+	return s
+}
+
+// Field describes one JSON property of a models struct.
+type Field struct {
+	Name     string       // JSON property name
+	GoName   string       // Go field name
+	Type     reflect.Type // Go type
+	Optional bool         // omitted from JSON when empty (omitempty/omitzero)
+	Format   string       // "uri" for validate:"url"
+	Enum     []string     // values from validate:"oneof=…"
+}
+
+// Fields lists the JSON properties of struct type t in declaration order,
+// skipping fields without a json name or tagged "-".
+func Fields(t reflect.Type) []Field {
+	var fields []Field
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag, ok := sf.Tag.Lookup("json")
+		if !sf.IsExported() || !ok || tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = sf.Name
+		}
+		f := Field{
+			Name:     name,
+			GoName:   sf.Name,
+			Type:     sf.Type,
+			Optional: strings.Contains(opts, "omitempty") || strings.Contains(opts, "omitzero"),
+		}
+		for _, rule := range strings.Split(sf.Tag.Get("validate"), ",") {
+			switch {
+			case rule == "url":
+				f.Format = "uri"
+			case strings.HasPrefix(rule, "oneof="):
+				f.Enum = strings.Fields(strings.TrimPrefix(rule, "oneof="))
+			}
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+// Marshal encodes s as indented JSON with a trailing newline.
+func Marshal(s *Schema) ([]byte, error) {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encode schema: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// Load reads a schema document written by Marshal.
+func Load(path string) (*Schema, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read schema: %w", err)
+	}
+	var s Schema
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parse schema: %w", err)
+	}
+	return &s, nil
+}
+
+// Write writes the generated schema to path atomically.
+func Write(path string) error {
+	data, err := Marshal(Generate())
+	if err != nil {
+		return err
+	}
+	return atomicfile.WriteFile(path, data, 0644, atomicfile.VerifyJSON[Schema]())
+}
+
+// resolve follows a local $ref into root's $defs.
+func resolve(root, s *Schema) (*Schema, error) {
+	if s == nil || s.Ref == "" {
+		return s, nil
+	}
+	name, ok := strings.CutPrefix(s.Ref, "#/$defs/")
+	if !ok {
+		return nil, fmt.Errorf("unsupported $ref %q", s.Ref)
+	}
+	def, ok := root.Defs[name]
+	if !ok {
+		return nil, fmt.Errorf("undefined $ref %q", s.Ref)
+	}
+	return def, nil
+}
@@ -0,0 +1,159 @@
+package schema
+
+import (
+	"bytes"
+	"os"
+	"slices"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	s := Generate()
+	release := s.Defs["Release"]
+	if release == nil {
+		t.Fatal("Release missing from $defs")
+	}
+
+	tests := []struct {
+		prop     string
+		typ      string
+		format   string
+		enum     int
+		required bool
+	}{
+		{"id", "string", "", 0, true},
+		{"link", "string", "uri", 0, true},
+		{"pubDate", "string", "date-time", 0, true},
+		{"content", "string", "", 0, false},
+		{"projectStatus", "string", "", 3, false},
+		{"feedStatus", "string", "", 2, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.prop, func(t *testing.T) {
+			p := release.Properties[tt.prop]
+			if p == nil {
+				t.Fatalf("property %q missing", tt.prop)
+			}
+			if typeString(p.Type) != tt.typ || p.Format != tt.format || len(p.Enum) != tt.enum {
+				t.Errorf("got type %v format %q enum %v", p.Type, p.Format, p.Enum)
+			}
+			if got := slices.Contains(release.Required, tt.prop); got != tt.required {
+				t.Errorf("required = %v, want %v", got, tt.required)
+			}
+		})
+	}
+
+	if got := typeString(s.Properties["releases"].Type); got != "array|null" {
+		t.Errorf("releases type = %s, want array|null", got)
+	}
+	if got := s.Properties["releases"].Items.Ref; got != "#/$defs/Release" {
+		t.Errorf("releases items = %q", got)
+	}
+}
+
+// TestPublishedSchema is the compatibility gate: the models must match the
+// published schema, and any change to them must come with the version bump
+// it requires and a regenerated schema/releases.schema.json.
+func TestPublishedSchema(t *testing.T) {
+	published, err := Load("../../schema/releases.schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	changes, err := Check(published, Generate())
+	for _, ch := range changes {
+		t.Log(ch)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want, err := os.ReadFile("../../schema/releases.schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := Marshal(Generate())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatal("schema/releases.schema.json is out of date; run: go run ./cmd/firehose schema -o schema/releases.schema.json")
+	}
+}
+
+func TestCheck(t *testing.T) {
+	str := func() *Schema { return &Schema{Type: "string"} }
+	base := func(version string) *Schema {
+		return &Schema{
+			Version: version,
+			Type:    "object",
+			Properties: map[string]*Schema{
+				"id":     str(),
+				"status": {Type: "string", Enum: []string{"success", "error"}},
+				"note":   str(),
+			},
+			Required: []string{"id", "status"},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		version  string
+		change   func(s *Schema)
+		breaking bool
+		wantErr  bool
+	}{
+		{"unchanged", "1.0.0", func(s *Schema) {}, false, false},
+		{"added property without bump", "1.0.0", func(s *Schema) { s.Properties["extra"] = str() }, false, true},
+		{"added property with minor bump", "1.1.0", func(s *Schema) { s.Properties["extra"] = str() }, false, false},
+		{"removed property with minor bump", "1.1.0", func(s *Schema) { delete(s.Properties, "note") }, true, true},
+		{"removed property with major bump", "2.0.0", func(s *Schema) { delete(s.Properties, "note") }, true, false},
+		{"retyped property", "1.1.0", func(s *Schema) { s.Properties["id"] = &Schema{Type: "integer"} }, true, true},
+		{"no longer required", "1.1.0", func(s *Schema) { s.Required = []string{"id"} }, true, true},
+		{"now required", "1.1.0", func(s *Schema) { s.Required = append(s.Required, "note") }, false, false},
+		{"enum value added", "1.1.0", func(s *Schema) {
+			s.Properties["status"].Enum = []string{"success", "error", "skipped"}
+		}, true, true},
+		{"enum value removed", "1.1.0", func(s *Schema) { s.Properties["status"].Enum = []string{"success"} }, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := base(tt.version)
+			tt.change(next)
+			changes, err := Check(base("1.0.0"), next)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			breaking := false
+			for _, ch := range changes {
+				breaking = breaking || ch.Breaking
+			}
+			if breaking != tt.breaking {
+				t.Errorf("breaking = %v, want %v (changes %v)", breaking, tt.breaking, changes)
+			}
+		})
+	}
+}
+
+func TestCheckResolvesRefs(t *testing.T) {
+	old := Generate()
+	next := Generate()
+	delete(next.Defs["Release"].Properties, "guid")
+	next.Version = "1.1.0"
+
+	changes, err := Check(old, next)
+	if err == nil {
+		t.Fatal("expected removal inside $defs to be breaking")
+	}
+	if !hasPath(changes, "$.releases[].guid") {
+		t.Errorf("changes = %v, want $.releases[].guid removed", changes)
+	}
+}
+
+func hasPath(changes []Change, path string) bool {
+	for _, ch := range changes {
+		if ch.Path == path {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,105 @@
+-- Schema of the SQLite export. Statements are idempotent; user_version
+-- records the schema revision for future migrations.
+
+CREATE TABLE IF NOT EXISTS projects (
+    id            INTEGER PRIMARY KEY,
+    repo          TEXT NOT NULL UNIQUE, -- GitHub org/repo, the landscape key
+    name          TEXT NOT NULL,
+    slug          TEXT NOT NULL,
+    description   TEXT,
+    status        TEXT,                 -- graduated, incubating, sandbox, or NULL for non-CNCF entries
+    category      TEXT,
+    subcategory   TEXT,
+    repo_url      TEXT,
+    homepage_url  TEXT,
+    blog_url      TEXT,
+    accepted      TEXT,                 -- Milestone dates, YYYY-MM-DD
+    incubating    TEXT,
+    graduated     TEXT
+);
+CREATE INDEX IF NOT EXISTS projects_name ON projects (name);
+CREATE INDEX IF NOT EXISTS projects_status ON projects (status);
+
+CREATE TABLE IF NOT EXISTS feeds (
+    id          INTEGER PRIMARY KEY,
+    url         TEXT NOT NULL UNIQUE,
+    kind        TEXT NOT NULL CHECK (kind IN ('release', 'blog')),
+    category    TEXT,
+    project_id  INTEGER REFERENCES projects (id),
+    pinned      INTEGER NOT NULL DEFAULT 0,
+    tracked     INTEGER NOT NULL DEFAULT 1 -- 0 once the feed leaves feeds.yaml; its items are kept
+);
+CREATE INDEX IF NOT EXISTS feeds_project ON feeds (project_id);
+
+-- releases and news share a layout; items are upserted, so the tables keep
+-- history beyond the window the feeds currently publish.
+CREATE TABLE IF NOT EXISTS releases (
+    id               INTEGER PRIMARY KEY,
+    feed_id          INTEGER NOT NULL REFERENCES feeds (id),
+    item_id          TEXT NOT NULL,       -- GUID, or link when the feed has none
+    title            TEXT NOT NULL,
+    link             TEXT NOT NULL,
+    pub_date         TEXT NOT NULL,       -- RFC 3339, UTC
+    content          TEXT,
+    content_snippet  TEXT,
+    first_seen       TEXT NOT NULL,
+    last_seen        TEXT NOT NULL,
+    UNIQUE (feed_id, item_id)
+);
+CREATE INDEX IF NOT EXISTS releases_pub_date ON releases (pub_date);
+
+CREATE TABLE IF NOT EXISTS news (
+    id               INTEGER PRIMARY KEY,
+    feed_id          INTEGER NOT NULL REFERENCES feeds (id),
+    item_id          TEXT NOT NULL,
+    title            TEXT NOT NULL,
+    link             TEXT NOT NULL,
+    pub_date         TEXT NOT NULL,
+    content          TEXT,
+    content_snippet  TEXT,
+    first_seen       TEXT NOT NULL,
+    last_seen        TEXT NOT NULL,
+    UNIQUE (feed_id, item_id)
+);
+CREATE INDEX IF NOT EXISTS news_pub_date ON news (pub_date);
+
+CREATE TABLE IF NOT EXISTS runs (
+    id                INTEGER PRIMARY KEY,
+    generated_at      TEXT NOT NULL UNIQUE,
+    generated_by      TEXT,
+    schema_version    TEXT,
+    build_duration    TEXT,
+    feeds_total       INTEGER,
+    feeds_successful  INTEGER,
+    feeds_failed      INTEGER,
+    releases_total    INTEGER,
+    news_total        INTEGER
+);
+
+-- One row per feed per run: the FeedStatus history.
+CREATE TABLE IF NOT EXISTS fetch_runs (
+    id             INTEGER PRIMARY KEY,
+    run_id         INTEGER NOT NULL REFERENCES runs (id),
+    feed_id        INTEGER NOT NULL REFERENCES feeds (id),
+    status         TEXT NOT NULL,
+    error          TEXT,
+    error_type     TEXT,
+    entries_count  INTEGER NOT NULL DEFAULT 0,
+    fetched_at     TEXT,
+    duration       TEXT,
+    final_url      TEXT,
+    warnings       TEXT, -- JSON array of lint warnings
+    UNIQUE (run_id, feed_id)
+);
+CREATE INDEX IF NOT EXISTS fetch_runs_feed ON fetch_runs (feed_id, run_id);
+
+-- Full-text search over release and news titles and content. Rebuilt on
+-- every export; join on kind and item to releases.id or news.id.
+CREATE VIRTUAL TABLE IF NOT EXISTS search USING fts5 (
+    title,
+    content,
+    kind UNINDEXED,
+    item UNINDEXED
+);
+
+PRAGMA user_version = 1;
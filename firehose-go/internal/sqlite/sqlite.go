@@ -0,0 +1,323 @@
+// Package sqlite exports a run into a SQLite database with normalized tables
+// for projects, feeds, releases, news and per-feed fetch history, plus a
+// full-text index over item titles and content.
+//
+// The database is updated in place: projects, feeds and items are upserted
+// and every run appends its feed statuses, so keeping the file between runs
+// (as CI does with state/) builds up history the JSON output does not keep.
+// It uses the pure-Go modernc.org/sqlite driver, so builds stay CGO-free.
+package sqlite
+
+import (
+	"database/sql"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/castrojo/firehose-go/internal/models"
+	"github.com/castrojo/firehose-go/internal/urlutil"
+	_ "modernc.org/sqlite" // Registers the "sqlite" driver
+)
+
+//go:embed schema.sql
+var schemaSQL string
+
+// Open opens (creating if needed) the database at path and applies the schema.
+func Open(path string) (*sql.DB, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("create database directory: %w", err)
+	}
+	db, err := sql.Open("sqlite", path+"?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, fmt.Errorf("open database: %w", err)
+	}
+	if _, err := db.Exec(schemaSQL); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("apply schema: %w", err)
+	}
+	return db, nil
+}
+
+// OpenReadOnly opens an existing database for queries.
+func OpenReadOnly(path string) (*sql.DB, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("open database: %w", err)
+	}
+	db, err := sql.Open("sqlite", "file:"+path+"?mode=ro&_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, fmt.Errorf("open database: %w", err)
+	}
+	return db, nil
+}
+
+// Write records a run in the database at path, in a single transaction so
+// a failed export leaves the previous contents untouched.
+func Write(path string, o *models.OutputData, config *models.FeedConfig, landscapeData map[string]models.LandscapeProject) error {
+	db, err := Open(path)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	w := &writer{tx: tx, projects: make(map[string]int64), feeds: make(map[string]int64)}
+	steps := []struct {
+		name string
+		fn   func() error
+	}{
+		{"projects", func() error { return w.writeProjects(landscapeData) }},
+		{"feeds", func() error { return w.writeFeeds(config, landscapeData) }},
+		{"releases", func() error { return w.writeItems("releases", o.Releases, o.Metadata.GeneratedAt) }},
+		{"news", func() error { return w.writeItems("news", o.News, o.Metadata.GeneratedAt) }},
+		{"fetch runs", func() error { return w.writeRun(o) }},
+		{"search index", w.rebuildSearch},
+	}
+	for _, step := range steps {
+		if err := step.fn(); err != nil {
+			return fmt.Errorf("write %s: %w", step.name, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	return nil
+}
+
+// writer holds the transaction and the ids assigned so far.
+type writer struct {
+	tx       *sql.Tx
+	projects map[string]int64 // org/repo → projects.id
+	feeds    map[string]int64 // feed URL → feeds.id
+}
+
+// writeProjects upserts every landscape project, keyed by org/repo.
+func (w *writer) writeProjects(landscapeData map[string]models.LandscapeProject) error {
+	stmt, err := w.tx.Prepare(`
+		INSERT INTO projects (repo, name, slug, description, status, category, subcategory,
+			repo_url, homepage_url, blog_url, accepted, incubating, graduated)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (repo) DO UPDATE SET
+			name = excluded.name, slug = excluded.slug, description = excluded.description,
+			status = excluded.status, category = excluded.category, subcategory = excluded.subcategory,
+			repo_url = excluded.repo_url, homepage_url = excluded.homepage_url, blog_url = excluded.blog_url,
+			accepted = excluded.accepted, incubating = excluded.incubating, graduated = excluded.graduated
+		RETURNING id`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for repo, p := range landscapeData {
+		var id int64
+		err := stmt.QueryRow(repo, p.Name, models.Slug(p.Name), nullable(p.Description), nullable(p.Status),
+			nullable(p.Category), nullable(p.Subcategory), nullable(p.RepoURL), nullable(p.HomepageURL),
+			nullable(p.BlogURL), nullable(p.Accepted), nullable(p.Incubating), nullable(p.Graduated)).Scan(&id)
+		if err != nil {
+			return fmt.Errorf("%s: %w", repo, err)
+		}
+		w.projects[repo] = id
+	}
+	return nil
+}
+
+// writeFeeds upserts the configured feeds and marks feeds no longer in the
+// config as untracked. Projects are matched the way enrichment matches them:
+// by the org/repo in the feed URL, then by project name.
+func (w *writer) writeFeeds(config *models.FeedConfig, landscapeData map[string]models.LandscapeProject) error {
+	byName := make(map[string]string, len(landscapeData))
+	for repo, p := range landscapeData {
+		byName[p.Name] = repo
+	}
+	projectID := func(feedURL, name string) any {
+		if id, ok := w.projects[urlutil.ExtractOrgRepo(feedURL)]; ok {
+			return id
+		}
+		if id, ok := w.projects[byName[name]]; ok && name != "" {
+			return id
+		}
+		return nil
+	}
+
+	if _, err := w.tx.Exec(`UPDATE feeds SET tracked = 0`); err != nil {
+		return err
+	}
+	stmt, err := w.tx.Prepare(`
+		INSERT INTO feeds (url, kind, category, project_id, pinned, tracked)
+		VALUES (?, ?, ?, ?, ?, 1)
+		ON CONFLICT (url) DO UPDATE SET
+			kind = excluded.kind, category = excluded.category, project_id = excluded.project_id,
+			pinned = excluded.pinned, tracked = 1
+		RETURNING id`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	add := func(feedURL, kind, category string, project any, pinned bool) error {
+		var id int64
+		if err := stmt.QueryRow(feedURL, kind, nullable(category), project, pinned).Scan(&id); err != nil {
+			return fmt.Errorf("%s: %w", feedURL, err)
+		}
+		w.feeds[feedURL] = id
+		return nil
+	}
+	for _, f := range config.Feeds {
+		name := ""
+		if f.Project != nil {
+			name = *f.Project
+		}
+		if err := add(f.URL, "release", f.Category, projectID(f.URL, name), f.Pinned); err != nil {
+			return err
+		}
+	}
+	for _, b := range config.Blogs {
+		if err := add(b.URL, "blog", b.Category, projectID(b.URL, b.Project), b.Pinned); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// feedID returns the id of a feed, adding it untracked when it is not in the
+// config (items from a feed removed mid-run, or a test's partial config).
+func (w *writer) feedID(feedURL, kind string) (int64, error) {
+	if id, ok := w.feeds[feedURL]; ok {
+		return id, nil
+	}
+	var id int64
+	err := w.tx.QueryRow(`
+		INSERT INTO feeds (url, kind, tracked) VALUES (?, ?, 0)
+		ON CONFLICT (url) DO UPDATE SET url = excluded.url
+		RETURNING id`, feedURL, kind).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", feedURL, err)
+	}
+	w.feeds[feedURL] = id
+	return id, nil
+}
+
+// writeItems upserts releases or news items, keyed by feed and item id.
+// first_seen keeps the run that first recorded an item.
+func (w *writer) writeItems(table string, items []models.Release, seen string) error {
+	kind := "release"
+	if table == "news" {
+		kind = "blog"
+	}
+	stmt, err := w.tx.Prepare(`
+		INSERT INTO ` + table + ` (feed_id, item_id, title, link, pub_date, content, content_snippet, first_seen, last_seen)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (feed_id, item_id) DO UPDATE SET
+			title = excluded.title, link = excluded.link, pub_date = excluded.pub_date,
+			content = excluded.content, content_snippet = excluded.content_snippet,
+			last_seen = excluded.last_seen`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, r := range items {
+		feedID, err := w.feedID(r.FeedURL, kind)
+		if err != nil {
+			return err
+		}
+		_, err = stmt.Exec(feedID, itemID(r), r.Title, r.Link, r.PubDate.UTC().Format(time.RFC3339),
+			nullable(r.Content), nullable(r.ContentSnippet), seen, seen)
+		if err != nil {
+			return fmt.Errorf("%s: %w", r.Link, err)
+		}
+	}
+	return nil
+}
+
+// writeRun records the run and the status of every feed in it. Re-exporting
+// the same output replaces that run's rows rather than duplicating them.
+func (w *writer) writeRun(o *models.OutputData) error {
+	m := o.Metadata
+	var runID int64
+	err := w.tx.QueryRow(`
+		INSERT INTO runs (generated_at, generated_by, schema_version, build_duration,
+			feeds_total, feeds_successful, feeds_failed, releases_total, news_total)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (generated_at) DO UPDATE SET
+			generated_by = excluded.generated_by, schema_version = excluded.schema_version,
+			build_duration = excluded.build_duration, feeds_total = excluded.feeds_total,
+			feeds_successful = excluded.feeds_successful, feeds_failed = excluded.feeds_failed,
+			releases_total = excluded.releases_total, news_total = excluded.news_total
+		RETURNING id`,
+		m.GeneratedAt, m.GeneratedBy, m.SchemaVersion, m.BuildDuration, m.Stats.FeedsTotal,
+		m.Stats.FeedsSuccessful, m.Stats.FeedsFailed, m.Stats.ReleasesTotal, m.Stats.NewsTotal).Scan(&runID)
+	if err != nil {
+		return err
+	}
+	if _, err := w.tx.Exec(`DELETE FROM fetch_runs WHERE run_id = ?`, runID); err != nil {
+		return err
+	}
+
+	stmt, err := w.tx.Prepare(`
+		INSERT INTO fetch_runs (run_id, feed_id, status, error, error_type, entries_count,
+			fetched_at, duration, final_url, warnings)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, fs := range o.Feeds {
+		feedID, err := w.feedID(fs.FeedURL, "release")
+		if err != nil {
+			return err
+		}
+		var warnings any
+		if len(fs.Warnings) > 0 {
+			data, err := json.Marshal(fs.Warnings)
+			if err != nil {
+				return fmt.Errorf("%s: encode warnings: %w", fs.FeedURL, err)
+			}
+			warnings = string(data)
+		}
+		_, err = stmt.Exec(runID, feedID, fs.Status, nullable(fs.Error), nullable(fs.ErrorType), fs.EntriesCount,
+			nullable(fs.FetchedAt), nullable(fs.Duration), nullable(fs.FinalURL), warnings)
+		if err != nil {
+			return fmt.Errorf("%s: %w", fs.FeedURL, err)
+		}
+	}
+	return nil
+}
+
+// rebuildSearch refills the full-text index from the item tables.
+func (w *writer) rebuildSearch() error {
+	_, err := w.tx.Exec(`
+		DELETE FROM search;
+		INSERT INTO search (title, content, kind, item)
+			SELECT title, coalesce(content, content_snippet, ''), 'release', id FROM releases;
+		INSERT INTO search (title, content, kind, item)
+			SELECT title, coalesce(content, content_snippet, ''), 'news', id FROM news;`)
+	return err
+}
+
+// itemID is the stable key of an item within its feed.
+func itemID(r models.Release) string {
+	if r.GUID != "" {
+		return r.GUID
+	}
+	if r.ID != "" {
+		return r.ID
+	}
+	return r.Link
+}
+
+// nullable maps empty strings to NULL.
+func nullable(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
@@ -0,0 +1,100 @@
+package sqlite
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/castrojo/firehose-go/internal/models"
+)
+
+const k8sFeed = "https://github.com/kubernetes/kubernetes/releases.atom"
+
+func testInput(generatedAt string, releases ...models.Release) (*models.OutputData, *models.FeedConfig, map[string]models.LandscapeProject) {
+	out := &models.OutputData{
+		Metadata: models.Metadata{GeneratedAt: generatedAt, SchemaVersion: models.SchemaVersion},
+		Releases: releases,
+		News: []models.Release{
+			{ID: "post", Title: "Kubernetes blog post", Link: "https://kubernetes.io/blog/post", Content: "<p>Sidecar containers</p>",
+				PubDate: time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC), FeedURL: "https://kubernetes.io/feed.xml"},
+		},
+		Feeds: []models.FeedStatus{
+			{FeedURL: k8sFeed, Status: "success", EntriesCount: len(releases), FetchedAt: generatedAt, Duration: "1s",
+				Warnings: []models.FeedWarning{{Code: "http-link", Count: 1}}},
+			{FeedURL: "https://kubernetes.io/feed.xml", Status: "error", Error: "404", ErrorType: "network", FetchedAt: generatedAt, Duration: "1s"},
+		},
+	}
+	config := &models.FeedConfig{
+		Feeds: []models.FeedSource{{URL: k8sFeed, Category: "graduated"}},
+		Blogs: []models.BlogSource{{URL: "https://kubernetes.io/feed.xml", Category: "graduated", Project: "Kubernetes"}},
+	}
+	landscapeData := map[string]models.LandscapeProject{
+		"kubernetes/kubernetes": {Name: "Kubernetes", Status: "graduated", Category: "Orchestration & Management", Graduated: "2018-03-06"},
+	}
+	return out, config, landscapeData
+}
+
+func release(tag, content string) models.Release {
+	return models.Release{ID: tag, GUID: "tag:" + tag, Title: tag, Content: content,
+		Link: "https://github.com/kubernetes/kubernetes/releases/tag/" + tag, FeedURL: k8sFeed,
+		PubDate: time.Date(2026, 4, 17, 22, 30, 0, 0, time.FixedZone("CEST", 2*3600))}
+}
+
+func TestWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "firehose.db")
+
+	first := release("v1.30.0", "Adds sidecar containers")
+	out, config, landscapeData := testInput("2026-04-18T00:00:00Z", first)
+	if err := Write(path, out, config, landscapeData); err != nil {
+		t.Fatalf("first write: %v", err)
+	}
+	// The second run no longer lists v1.30.0 and drops the blog from the config.
+	second := release("v1.31.0", "Fixes CVE-2026-0001")
+	out, config, landscapeData = testInput("2026-04-19T00:00:00Z", second)
+	config.Blogs = nil
+	if err := Write(path, out, config, landscapeData); err != nil {
+		t.Fatalf("second write: %v", err)
+	}
+	// Re-exporting a run replaces its fetch history instead of duplicating it.
+	if err := Write(path, out, config, landscapeData); err != nil {
+		t.Fatalf("repeat write: %v", err)
+	}
+
+	db, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{"releases kept across runs", `SELECT group_concat(title, ',') FROM (SELECT title FROM releases ORDER BY title)`, "v1.30.0,v1.31.0"},
+		{"pub_date in UTC", `SELECT pub_date FROM releases WHERE title = 'v1.30.0'`, "2026-04-17T20:30:00Z"},
+		{"first and last seen", `SELECT first_seen || ' ' || last_seen FROM releases WHERE title = 'v1.30.0'`, "2026-04-18T00:00:00Z 2026-04-18T00:00:00Z"},
+		{"release project", `SELECT p.name || ' ' || p.status FROM releases r JOIN feeds f ON f.id = r.feed_id JOIN projects p ON p.id = f.project_id WHERE r.title = 'v1.31.0'`, "Kubernetes graduated"},
+		{"blog matched by name", `SELECT p.name FROM feeds f JOIN projects p ON p.id = f.project_id WHERE f.kind = 'blog'`, "Kubernetes"},
+		{"removed feed untracked", `SELECT tracked FROM feeds WHERE kind = 'blog'`, "0"},
+		{"news", `SELECT count(*) FROM news`, "1"},
+		{"runs", `SELECT count(*) FROM runs`, "2"},
+		{"fetch history", `SELECT count(*) FROM fetch_runs`, "4"},
+		{"fetch error", `SELECT error_type FROM fetch_runs WHERE status = 'error' LIMIT 1`, "network"},
+		{"fetch warnings", `SELECT warnings FROM fetch_runs WHERE run_id = 1 AND status = 'success'`, `[{"code":"http-link","count":1}]`},
+		{"search titles", `SELECT r.title FROM search s JOIN releases r ON s.kind = 'release' AND r.id = s.item WHERE search MATCH 'CVE'`, "v1.31.0"},
+		{"search content", `SELECT group_concat(kind, ',') FROM (SELECT kind FROM search WHERE search MATCH 'sidecar' ORDER BY kind)`, "news,release"},
+		{"schema version", `PRAGMA user_version`, "1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			if err := db.QueryRow(tt.query).Scan(&got); err != nil {
+				t.Fatalf("query: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,282 @@
+package sync
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/castrojo/firehose-go/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// totalCommentRe matches the "# Total: N release feeds, M blog feeds" header line.
+var totalCommentRe = regexp.MustCompile(`# Total: \d+ release feeds, \d+ blog feeds`)
+
+// configDoc is a comment-preserving view of feeds.yaml.
+// Edits are applied to the yaml.Node tree so that comments, grouping and
+// manual annotations survive a sync run and the PR diff shows only real changes.
+type configDoc struct {
+	root yaml.Node
+}
+
+// loadConfigDoc reads feeds.yaml into a node tree.
+func loadConfigDoc(path string) (*configDoc, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read config: %w", err)
+	}
+	return parseConfigDoc(data)
+}
+
+// parseConfigDoc parses feeds.yaml content into a node tree.
+func parseConfigDoc(data []byte) (*configDoc, error) {
+	doc := &configDoc{}
+	if err := yaml.Unmarshal(data, &doc.root); err != nil {
+		return nil, fmt.Errorf("parse YAML: %w", err)
+	}
+	if doc.root.Kind == 0 {
+		// Empty file: start from an empty mapping document.
+		doc.root = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	if doc.root.Kind != yaml.DocumentNode || len(doc.root.Content) == 0 || doc.root.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("parse YAML: top level is not a mapping")
+	}
+	return doc, nil
+}
+
+// decode converts the node tree into a FeedConfig.
+func (d *configDoc) decode() (*models.FeedConfig, error) {
+	var config models.FeedConfig
+	if err := d.root.Decode(&config); err != nil {
+		return nil, fmt.Errorf("decode config: %w", err)
+	}
+	return &config, nil
+}
+
+// encode serialises the node tree using the file's 4-space indentation.
+func (d *configDoc) encode() ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(4)
+	if err := enc.Encode(&d.root); err != nil {
+		return nil, fmt.Errorf("marshal YAML: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("marshal YAML: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// verifyConfig checks that written config data parses back into a feed config.
+func verifyConfig(r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	doc, err := parseConfigDoc(data)
+	if err != nil {
+		return err
+	}
+	_, err = doc.decode()
+	return err
+}
+
+// sequence returns the sequence node stored under key, creating it when create is set.
+func (d *configDoc) sequence(key string, create bool) *yaml.Node {
+	m := d.root.Content[0]
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			seq := m.Content[i+1]
+			if seq.Kind != yaml.SequenceNode {
+				if !create {
+					return nil
+				}
+				// "blogs:" with no entries decodes as a null scalar.
+				*seq = yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", HeadComment: seq.HeadComment, LineComment: seq.LineComment}
+			}
+			return seq
+		}
+	}
+	if !create {
+		return nil
+	}
+	seq := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+	m.Content = append(m.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, seq)
+	return seq
+}
+
+// count returns the number of entries under key.
+func (d *configDoc) count(key string) int {
+	if seq := d.sequence(key, false); seq != nil {
+		return len(seq.Content)
+	}
+	return 0
+}
+
+// removeFeed removes every release feed entry with the given URL.
+func (d *configDoc) removeFeed(feedURL string) bool {
+	return d.removeWhere("feeds", func(entry *yaml.Node) bool {
+		return mappingValue(entry, "url") == feedURL
+	})
+}
+
+// removeBlog removes every blog entry for the given project name.
+func (d *configDoc) removeBlog(project string) bool {
+	return d.removeWhere("blogs", func(entry *yaml.Node) bool {
+		return mappingValue(entry, "project") == project
+	})
+}
+
+// setFeedCategory rewrites the category of every release feed with the given URL.
+func (d *configDoc) setFeedCategory(feedURL, category string) bool {
+	return d.setField("feeds", "url", feedURL, "category", category)
+}
+
+// setBlogCategory rewrites the category of every blog entry for the given project.
+func (d *configDoc) setBlogCategory(project, category string) bool {
+	return d.setField("blogs", "project", project, "category", category)
+}
+
+// addFeed inserts a release feed in sorted URL order within its category.
+func (d *configDoc) addFeed(src models.FeedSource) error {
+	return d.insertSorted("feeds", src, src.Category, "url", src.URL)
+}
+
+// addBlog inserts a blog feed in sorted project order within its category.
+func (d *configDoc) addBlog(src models.BlogSource) error {
+	return d.insertSorted("blogs", src, src.Category, "project", src.Project)
+}
+
+// setTotals rewrites the "# Total: ..." header line, if present, to the current counts.
+func (d *configDoc) setTotals() {
+	total := fmt.Sprintf("# Total: %d release feeds, %d blog feeds", d.count("feeds"), d.count("blogs"))
+	nodes := []*yaml.Node{&d.root, d.root.Content[0]}
+	if m := d.root.Content[0]; len(m.Content) > 0 {
+		nodes = append(nodes, m.Content[0])
+	}
+	for _, n := range nodes {
+		n.HeadComment = totalCommentRe.ReplaceAllString(n.HeadComment, total)
+	}
+}
+
+// setField sets field to value on every entry under key whose matchField equals matchValue.
+// Only the scalar value is replaced, so line comments on the field are kept.
+func (d *configDoc) setField(key, matchField, matchValue, field, value string) bool {
+	seq := d.sequence(key, false)
+	if seq == nil {
+		return false
+	}
+	changed := false
+	for _, entry := range seq.Content {
+		if mappingValue(entry, matchField) != matchValue {
+			continue
+		}
+		for i := 0; i+1 < len(entry.Content); i += 2 {
+			if entry.Content[i].Value == field && entry.Content[i+1].Value != value {
+				entry.Content[i+1].Value = value
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+// removeWhere deletes entries of the sequence under key that match.
+// A head comment on a removed entry is carried over to the entry that follows
+// it so group headings are not lost along with the first entry of a group.
+func (d *configDoc) removeWhere(key string, match func(*yaml.Node) bool) bool {
+	seq := d.sequence(key, false)
+	if seq == nil {
+		return false
+	}
+	removed := false
+	var pendingComment string
+	kept := seq.Content[:0]
+	for _, entry := range seq.Content {
+		if match(entry) {
+			removed = true
+			pendingComment = joinComments(pendingComment, entry.HeadComment)
+			continue
+		}
+		if pendingComment != "" {
+			entry.HeadComment = joinComments(pendingComment, entry.HeadComment)
+			pendingComment = ""
+		}
+		kept = append(kept, entry)
+	}
+	seq.Content = kept
+	if pendingComment != "" {
+		seq.FootComment = joinComments(pendingComment, seq.FootComment)
+	}
+	return removed
+}
+
+// insertSorted encodes value and inserts it into the sequence under key.
+// It is placed before the first entry of the same category whose sortField
+// compares greater than sortValue, or after the last entry of that category.
+// Entries for a category not yet in the file are appended at the end.
+func (d *configDoc) insertSorted(key string, value interface{}, category, sortField, sortValue string) error {
+	var entry yaml.Node
+	if err := entry.Encode(value); err != nil {
+		return fmt.Errorf("encode %s entry: %w", key, err)
+	}
+	seq := d.sequence(key, true)
+
+	pos := len(seq.Content)
+	lastInCategory := -1
+	for i, existing := range seq.Content {
+		if mappingValue(existing, "category") != category {
+			continue
+		}
+		if strings.ToLower(mappingValue(existing, sortField)) > strings.ToLower(sortValue) {
+			pos = i
+			lastInCategory = -1
+			break
+		}
+		lastInCategory = i
+	}
+	if lastInCategory >= 0 {
+		pos = lastInCategory + 1
+	}
+
+	// Keep a group heading attached to the top of its group.
+	if pos < len(seq.Content) {
+		next := seq.Content[pos]
+		if mappingValue(next, "category") == category && (pos == 0 || mappingValue(seq.Content[pos-1], "category") != category) {
+			entry.HeadComment, next.HeadComment = next.HeadComment, ""
+		}
+	}
+
+	seq.Content = append(seq.Content, nil)
+	copy(seq.Content[pos+1:], seq.Content[pos:])
+	seq.Content[pos] = &entry
+	return nil
+}
+
+// mappingValue returns the scalar value stored under key in a mapping node.
+func mappingValue(n *yaml.Node, key string) string {
+	if n == nil || n.Kind != yaml.MappingNode {
+		return ""
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return n.Content[i+1].Value
+		}
+	}
+	return ""
+}
+
+// joinComments concatenates two comment blocks, skipping empty ones.
+func joinComments(a, b string) string {
+	switch {
+	case a == "":
+		return b
+	case b == "":
+		return a
+	default:
+		return a + "\n" + b
+	}
+}
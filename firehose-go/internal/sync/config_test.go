@@ -0,0 +1,131 @@
+package sync
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/castrojo/firehose-go/internal/models"
+)
+
+const testConfig = `# Feed Configuration for The Firehose (Go)
+# Total: 3 release feeds, 1 blog feeds
+
+feeds:
+    # Graduated projects
+    - url: https://github.com/argoproj/argo-cd/releases.atom
+      category: graduated
+    - url: https://github.com/envoyproxy/envoy/releases.atom
+      category: graduated # pinned by hand
+    # Sandbox projects
+    - url: https://github.com/akri/akri/releases.atom
+      category: sandbox
+blogs:
+    - url: https://backstage.io/blog/rss.xml
+      category: incubating
+      project: Backstage
+      blog_url: https://backstage.io/blog
+`
+
+func TestConfigDocRoundTrip(t *testing.T) {
+	doc, err := parseConfigDoc([]byte(testConfig))
+	if err != nil {
+		t.Fatalf("parseConfigDoc() error = %v", err)
+	}
+	got, err := doc.encode()
+	if err != nil {
+		t.Fatalf("encode() error = %v", err)
+	}
+	if string(got) != testConfig {
+		t.Errorf("round trip changed the file:\n%s", got)
+	}
+
+	// The real config must also survive an unedited round trip byte-for-byte.
+	data, err := os.ReadFile("../../config/feeds.yaml")
+	if err != nil {
+		t.Fatalf("read feeds.yaml: %v", err)
+	}
+	doc, err = parseConfigDoc(data)
+	if err != nil {
+		t.Fatalf("parseConfigDoc(feeds.yaml) error = %v", err)
+	}
+	got, err = doc.encode()
+	if err != nil {
+		t.Fatalf("encode(feeds.yaml) error = %v", err)
+	}
+	if string(got) != string(data) {
+		t.Error("round trip of config/feeds.yaml is not byte-identical")
+	}
+}
+
+func TestConfigDocEdits(t *testing.T) {
+	doc, err := parseConfigDoc([]byte(testConfig))
+	if err != nil {
+		t.Fatalf("parseConfigDoc() error = %v", err)
+	}
+
+	if err := doc.addFeed(models.FeedSource{URL: "https://github.com/cilium/cilium/releases.atom", Category: "graduated"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.addFeed(models.FeedSource{URL: "https://github.com/aaa/aaa/releases.atom", Category: "sandbox"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.addFeed(models.FeedSource{URL: "https://github.com/zot/zot/releases.atom", Category: "incubating"}); err != nil {
+		t.Fatal(err)
+	}
+	if !doc.removeFeed("https://github.com/argoproj/argo-cd/releases.atom") {
+		t.Error("removeFeed() = false, want true")
+	}
+	if doc.removeBlog("Nonexistent") {
+		t.Error("removeBlog(Nonexistent) = true, want false")
+	}
+	if err := doc.addBlog(models.BlogSource{URL: "https://akri.sh/feed.xml", Category: "sandbox", Project: "Akri"}); err != nil {
+		t.Fatal(err)
+	}
+	doc.setTotals()
+
+	got, err := doc.encode()
+	if err != nil {
+		t.Fatalf("encode() error = %v", err)
+	}
+
+	want := `# Feed Configuration for The Firehose (Go)
+# Total: 5 release feeds, 2 blog feeds
+
+feeds:
+    # Graduated projects
+    - url: https://github.com/cilium/cilium/releases.atom
+      category: graduated
+    - url: https://github.com/envoyproxy/envoy/releases.atom
+      category: graduated # pinned by hand
+    # Sandbox projects
+    - url: https://github.com/aaa/aaa/releases.atom
+      category: sandbox
+    - url: https://github.com/akri/akri/releases.atom
+      category: sandbox
+    - url: https://github.com/zot/zot/releases.atom
+      category: incubating
+blogs:
+    - url: https://backstage.io/blog/rss.xml
+      category: incubating
+      project: Backstage
+      blog_url: https://backstage.io/blog
+    - url: https://akri.sh/feed.xml
+      category: sandbox
+      project: Akri
+`
+	if string(got) != want {
+		t.Errorf("edited config mismatch\n got:\n%s\nwant:\n%s", got, want)
+	}
+
+	config, err := doc.decode()
+	if err != nil {
+		t.Fatalf("decode() error = %v", err)
+	}
+	if len(config.Feeds) != 5 || len(config.Blogs) != 2 {
+		t.Errorf("decode() = %d feeds, %d blogs; want 5, 2", len(config.Feeds), len(config.Blogs))
+	}
+	if !strings.Contains(string(got), "# pinned by hand") {
+		t.Error("line comment was dropped")
+	}
+}
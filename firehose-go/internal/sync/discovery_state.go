@@ -0,0 +1,114 @@
+package sync
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+
+	"github.com/castrojo/firehose-go/internal/atomicfile"
+	"github.com/castrojo/firehose-go/internal/blog"
+)
+
+// Blog discovery back-off: 1 week after the first failure, doubling up to the cap.
+const (
+	discoveryBackoffBase = 7 * 24 * time.Hour
+	discoveryBackoffMax  = 16 * discoveryBackoffBase
+	// discoverySlack lets a weekly run that starts slightly early still count as due.
+	discoverySlack = 24 * time.Hour
+)
+
+// DiscoveryState records failed blog discovery attempts between sync runs.
+type DiscoveryState struct {
+	Blogs map[string]*DiscoveryRecord `json:"blogs"` // keyed by blog URL
+}
+
+// DiscoveryRecord tracks consecutive discovery failures for one blog.
+type DiscoveryRecord struct {
+	Project      string    `json:"project"`
+	Attempts     int       `json:"attempts"`
+	LastAttempt  time.Time `json:"lastAttempt"`
+	NextAttempt  time.Time `json:"nextAttempt"`
+	LastStrategy string    `json:"lastStrategy,omitempty"` // strategy probed last in the latest attempt
+	Reason       string    `json:"reason,omitempty"`
+}
+
+// LoadDiscoveryState reads the state file. A missing file yields an empty state.
+func LoadDiscoveryState(path string) (*DiscoveryState, error) {
+	state := &DiscoveryState{Blogs: make(map[string]*DiscoveryRecord)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read discovery state: %w", err)
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("parse discovery state: %w", err)
+	}
+	if state.Blogs == nil {
+		state.Blogs = make(map[string]*DiscoveryRecord)
+	}
+	return state, nil
+}
+
+// Save writes the state file, creating its directory if needed.
+func (s *DiscoveryState) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal discovery state: %w", err)
+	}
+	return atomicfile.WriteFile(path, append(data, '\n'), 0644, atomicfile.VerifyJSON[DiscoveryState]())
+}
+
+// due reports whether blogURL may be probed at now, returning its record if any.
+func (s *DiscoveryState) due(blogURL string, now time.Time) (*DiscoveryRecord, bool) {
+	rec, ok := s.Blogs[blogURL]
+	if !ok {
+		return nil, true
+	}
+	return rec, !now.Add(discoverySlack).Before(rec.NextAttempt)
+}
+
+// recordFailure bumps the attempt count for blogURL and schedules the next probe.
+func (s *DiscoveryState) recordFailure(blogURL, project string, res blog.Result, now time.Time) *DiscoveryRecord {
+	rec, ok := s.Blogs[blogURL]
+	if !ok {
+		rec = &DiscoveryRecord{}
+		s.Blogs[blogURL] = rec
+	}
+	rec.Project = project
+	rec.Attempts++
+	rec.LastAttempt = now.UTC()
+	rec.NextAttempt = now.UTC().Add(discoveryBackoff(rec.Attempts))
+	rec.Reason = res.Reason
+	if len(res.Tried) > 0 {
+		rec.LastStrategy = res.Tried[len(res.Tried)-1]
+	}
+	return rec
+}
+
+// recordSuccess forgets blogURL; once added to feeds.yaml it is no longer probed.
+func (s *DiscoveryState) recordSuccess(blogURL string) {
+	delete(s.Blogs, blogURL)
+}
+
+// prune drops records for blogs that are no longer discovery candidates.
+func (s *DiscoveryState) prune(keep map[string]bool) {
+	for blogURL := range s.Blogs {
+		if !keep[blogURL] {
+			delete(s.Blogs, blogURL)
+		}
+	}
+}
+
+// discoveryBackoff returns the wait after the given number of consecutive failures.
+func discoveryBackoff(attempts int) time.Duration {
+	d := discoveryBackoffBase
+	for i := 1; i < attempts && d < discoveryBackoffMax; i++ {
+		d *= 2
+	}
+	return min(d, discoveryBackoffMax)
+}
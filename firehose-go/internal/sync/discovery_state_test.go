@@ -0,0 +1,107 @@
+package sync
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/castrojo/firehose-go/internal/blog"
+	"github.com/castrojo/firehose-go/internal/models"
+)
+
+func TestDiscoveryBackoff(t *testing.T) {
+	week := 7 * 24 * time.Hour
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, week},
+		{2, 2 * week},
+		{3, 4 * week},
+		{5, 16 * week},
+		{12, 16 * week},
+	}
+	for _, tt := range tests {
+		if got := discoveryBackoff(tt.attempts); got != tt.want {
+			t.Errorf("discoveryBackoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestSyncBlogsBackoff(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	now := time.Date(2026, 3, 2, 4, 0, 0, 0, time.UTC)
+	statePath := filepath.Join(t.TempDir(), "state", "blog-discovery.json")
+	state, err := LoadDiscoveryState(statePath)
+	if err != nil {
+		t.Fatalf("LoadDiscoveryState() error = %v", err)
+	}
+	// Dead Blog failed twice and is not due again until two weeks from its last attempt.
+	state.Blogs["https://dead.example/blog"] = &DiscoveryRecord{
+		Project:     "Dead Blog",
+		Attempts:    2,
+		LastAttempt: now.Add(-7 * 24 * time.Hour),
+		NextAttempt: now.Add(7 * 24 * time.Hour),
+		Reason:      "no valid feed among 9 candidates",
+	}
+	// A record for a blog that is no longer a candidate is pruned.
+	state.Blogs["https://gone.example"] = &DiscoveryRecord{Project: "Gone", Attempts: 1}
+
+	config := &models.FeedConfig{}
+	landscapeData := map[string]models.LandscapeProject{
+		"dead/blog":  {Name: "Dead Blog", Status: "sandbox", BlogURL: "https://dead.example/blog"},
+		"fresh/blog": {Name: "Fresh Blog", Status: "sandbox", BlogURL: server.URL},
+	}
+
+	added, removed, failed, deferred := syncBlogs(config, landscapeData, state, now)
+
+	if len(added) != 0 || len(removed) != 0 {
+		t.Errorf("added = %+v, removed = %+v; want none", added, removed)
+	}
+	if len(deferred) != 1 || deferred[0].Name != "Dead Blog" || deferred[0].Attempts != 2 {
+		t.Errorf("deferred = %+v, want Dead Blog with 2 attempts", deferred)
+	}
+	if len(failed) != 1 || failed[0].Name != "Fresh Blog" || failed[0].Attempts != 1 {
+		t.Fatalf("failed = %+v, want Fresh Blog with 1 attempt", failed)
+	}
+	// No platform or <link> matched, so the generic suffixes were probed last.
+	if failed[0].Reason == "" || failed[0].Strategy != blog.StrategySuffix {
+		t.Errorf("failed entry = %+v, want a reason and last strategy %q", failed[0], blog.StrategySuffix)
+	}
+	body := (&SyncResult{BlogsDiscoveryFailed: failed}).Markdown()
+	if want := "| Fresh Blog | " + server.URL + " | 1 | suffix | "; !strings.Contains(body, want) {
+		t.Errorf("failure table missing %q:\n%s", want, body)
+	}
+	if want := now.Add(7 * 24 * time.Hour).Format(time.RFC3339); failed[0].NextAttempt != want {
+		t.Errorf("NextAttempt = %q, want %q", failed[0].NextAttempt, want)
+	}
+	if _, ok := state.Blogs["https://gone.example"]; ok {
+		t.Error("stale record was not pruned")
+	}
+
+	if err := state.Save(statePath); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	reloaded, err := LoadDiscoveryState(statePath)
+	if err != nil {
+		t.Fatalf("LoadDiscoveryState() error = %v", err)
+	}
+	if rec := reloaded.Blogs[server.URL]; len(reloaded.Blogs) != 2 || rec.Attempts != 1 || rec.LastStrategy != blog.StrategySuffix {
+		t.Errorf("reloaded state = %+v", reloaded.Blogs)
+	}
+
+	// A week later (run starting an hour early) the fresh blog is due again.
+	if _, due := reloaded.due(server.URL, now.Add(7*24*time.Hour-time.Hour)); !due {
+		t.Error("blog not due one week after its first failure")
+	}
+	if _, due := reloaded.due("https://dead.example/blog", now.Add(3*24*time.Hour)); due {
+		t.Error("backing-off blog reported as due")
+	}
+}
@@ -0,0 +1,189 @@
+package sync
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/castrojo/firehose-go/internal/models"
+	"github.com/castrojo/firehose-go/internal/opml"
+	"github.com/castrojo/firehose-go/internal/urlutil"
+)
+
+// ImportOptions configures an OPML import.
+type ImportOptions struct {
+	// Category is used for subscriptions whose maturity cannot be worked out
+	// from the landscape or from an enclosing graduated/incubating/sandbox
+	// folder. Empty skips them.
+	Category string
+	// DryRun leaves feeds.yaml untouched; ImportResult.Diff shows the edit.
+	DryRun bool
+}
+
+// ImportResult describes what an OPML import did.
+type ImportResult struct {
+	Feeds   []ImportEntry `json:"feeds"`   // Added as release feeds
+	Blogs   []ImportEntry `json:"blogs"`   // Added as blogs
+	Skipped []ImportEntry `json:"skipped"` // Not added; Reason says why
+	Diff    string        `json:"-"`       // Unified diff of feeds.yaml (dry runs only)
+}
+
+// ImportEntry is one OPML subscription and what happened to it.
+type ImportEntry struct {
+	Title    string `json:"title"`
+	URL      string `json:"url"`
+	Category string `json:"category,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// ImportOPML merges the subscriptions in doc into feeds.yaml as pinned feeds,
+// which landscape sync leaves alone. GitHub release and tag feeds become
+// release feeds and everything else becomes a blog. Subscriptions already in
+// the config, by URL or (for blogs) by project, are skipped. Edits go through
+// the comment-preserving node tree, like a sync run.
+func ImportOPML(configPath string, doc *opml.Document, landscapeData map[string]models.LandscapeProject, opts ImportOptions) (*ImportResult, error) {
+	cfg, err := loadConfigDoc(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("load config: %w", err)
+	}
+	config, err := cfg.decode()
+	if err != nil {
+		return nil, fmt.Errorf("load config: %w", err)
+	}
+
+	// Release feeds are known by repo, so tags.atom, http:// or trailing-slash
+	// variants of a tracked feed are not imported again.
+	knownURLs := make(map[string]bool)
+	knownRepos := make(map[string]bool)
+	for _, f := range config.Feeds {
+		knownURLs[f.URL] = true
+		if slug := urlutil.ExtractOrgRepo(f.URL); slug != "" {
+			knownRepos[strings.ToLower(slug)] = true
+		}
+	}
+	blogProjects := make(map[string]bool)
+	for _, b := range config.Blogs {
+		knownURLs[b.URL] = true
+		blogProjects[b.Project] = true
+	}
+	byName := make(map[string]models.LandscapeProject, len(landscapeData))
+	for _, proj := range landscapeData {
+		byName[proj.Name] = proj
+	}
+
+	result := &ImportResult{}
+	for _, sub := range doc.Subscriptions() {
+		title := sub.Title
+		if title == "" {
+			title = sub.Text
+		}
+		entry := ImportEntry{Title: title, URL: sub.XMLURL}
+
+		slug := urlutil.ExtractOrgRepo(sub.XMLURL)
+		isRelease := slug != "" && isReleaseFeed(sub.XMLURL)
+		proj, inLandscape := landscapeData[slug]
+		if !isRelease {
+			// Our own export titles blogs "<Project> Blog".
+			proj, inLandscape = byName[strings.TrimSuffix(title, " Blog")]
+		}
+		entry.Category = importCategory(proj.Status, sub.Folders, opts.Category)
+
+		switch {
+		case knownURLs[sub.XMLURL] || (isRelease && knownRepos[strings.ToLower(slug)]):
+			entry.Reason = "already in feeds.yaml"
+		case slug != "" && !isRelease:
+			entry.Reason = "GitHub page, not a releases.atom or tags.atom feed"
+		case !isRelease && title == "":
+			entry.Reason = "blog has no title to use as its project name"
+		case entry.Category == "":
+			entry.Reason = "maturity unknown (not in landscape, no tier folder); set a default category"
+		case !isRelease && blogProjects[blogProject(title, proj, inLandscape)]:
+			entry.Reason = "project already has a blog"
+		}
+		if entry.Reason != "" {
+			result.Skipped = append(result.Skipped, entry)
+			continue
+		}
+		knownURLs[sub.XMLURL] = true
+
+		if isRelease {
+			knownRepos[strings.ToLower(slug)] = true
+			src := models.FeedSource{URL: sub.XMLURL, Category: entry.Category, Pinned: true}
+			// Name projects the landscape does not know so items are labelled.
+			if !inLandscape && title != "" && title != slug {
+				src.Project = &title
+			}
+			if err := cfg.addFeed(src); err != nil {
+				return nil, err
+			}
+			result.Feeds = append(result.Feeds, entry)
+			continue
+		}
+		project := blogProject(title, proj, inLandscape)
+		blogProjects[project] = true
+		if err := cfg.addBlog(models.BlogSource{
+			URL:      sub.XMLURL,
+			Category: entry.Category,
+			Project:  project,
+			BlogURL:  sub.HTMLURL,
+			Pinned:   true,
+		}); err != nil {
+			return nil, err
+		}
+		result.Blogs = append(result.Blogs, entry)
+	}
+
+	sort.Slice(result.Skipped, func(i, j int) bool { return result.Skipped[i].URL < result.Skipped[j].URL })
+	if len(result.Feeds) == 0 && len(result.Blogs) == 0 {
+		return result, nil
+	}
+
+	if opts.DryRun {
+		if result.Diff, err = diffConfig(configPath, cfg); err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+	if err := writeConfig(configPath, cfg); err != nil {
+		return nil, fmt.Errorf("write config: %w", err)
+	}
+	return result, nil
+}
+
+// importCategory picks a subscription's maturity: the landscape status, else
+// the innermost enclosing folder named after a tier, else the fallback.
+func importCategory(status string, folders []string, fallback string) string {
+	if isTier(status) {
+		return status
+	}
+	for i := len(folders) - 1; i >= 0; i-- {
+		if tier := strings.ToLower(strings.TrimSpace(folders[i])); isTier(tier) {
+			return tier
+		}
+	}
+	return fallback
+}
+
+// isReleaseFeed reports whether feedURL is a GitHub releases or tags Atom feed
+// rather than the HTML page it is named after. A trailing slash is ignored.
+func isReleaseFeed(feedURL string) bool {
+	u, err := url.Parse(feedURL)
+	if err != nil {
+		return false
+	}
+	path := strings.TrimSuffix(u.Path, "/")
+	return strings.HasSuffix(path, "/releases.atom") || strings.HasSuffix(path, "/tags.atom")
+}
+
+func isTier(s string) bool {
+	return s == "graduated" || s == "incubating" || s == "sandbox"
+}
+
+// blogProject is the project name a blog is stored under.
+func blogProject(title string, proj models.LandscapeProject, inLandscape bool) string {
+	if inLandscape {
+		return proj.Name
+	}
+	return strings.TrimSuffix(title, " Blog")
+}
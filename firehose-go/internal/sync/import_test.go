@@ -0,0 +1,146 @@
+package sync
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/castrojo/firehose-go/internal/models"
+	"github.com/castrojo/firehose-go/internal/opml"
+)
+
+const testOPML = `<?xml version="1.0" encoding="ISO-8859-1"?>
+<opml version="2.0">
+  <head><title>My feeds</title></head>
+  <body>
+    <outline text="Incubating">
+      <outline text="Observability">
+        <outline type="rss" text="Tool" xmlUrl="https://github.com/example/tool/releases.atom" htmlUrl="https://tool.dev"/>
+        <outline type="rss" text="Tool Blog" xmlUrl="https://tool.dev/blog/index.xml" htmlUrl="https://tool.dev/blog"/>
+      </outline>
+    </outline>
+    <outline type="rss" text="Argo" xmlUrl="https://github.com/argoproj/argo-cd/releases.atom"/>
+    <outline type="rss" text="Backstage Blog" xmlUrl="https://backstage.io/other.xml"/>
+    <outline type="rss" text="Loose" xmlUrl="https://loose.example/feed.xml"/>
+    <outline type="rss" text="Akri" xmlUrl="https://github.com/project-akri/akri/tags.atom"/>
+    <outline type="rss" text="Argo tags" xmlUrl="http://github.com/ArgoProj/argo-cd/tags.atom"/>
+    <outline type="rss" text="Envoy" xmlUrl="https://github.com/envoyproxy/envoy/releases.atom/"/>
+    <outline type="rss" text="Tool again" xmlUrl="https://github.com/example/tool/releases"/>
+  </body>
+</opml>`
+
+func TestImportOPML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "feeds.yaml")
+	if err := os.WriteFile(path, []byte(testConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+	doc, err := opml.Parse(strings.NewReader(testOPML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	landscapeData := map[string]models.LandscapeProject{
+		"argoproj/argo-cd":    {Name: "Argo", Status: "graduated"},
+		"backstage/backstage": {Name: "Backstage", Status: "incubating"},
+		"project-akri/akri":   {Name: "Akri", Status: "sandbox"},
+	}
+
+	result, err := ImportOPML(path, doc, landscapeData, ImportOptions{})
+	if err != nil {
+		t.Fatalf("ImportOPML() error = %v", err)
+	}
+
+	if len(result.Feeds) != 2 || result.Feeds[0].Category != "incubating" || result.Feeds[1].Category != "sandbox" {
+		t.Errorf("feeds = %+v", result.Feeds)
+	}
+	if len(result.Blogs) != 1 || result.Blogs[0].URL != "https://tool.dev/blog/index.xml" {
+		t.Errorf("blogs = %+v", result.Blogs)
+	}
+	reasons := make(map[string]string)
+	for _, s := range result.Skipped {
+		reasons[s.URL] = s.Reason
+	}
+	for url, want := range map[string]string{
+		"https://github.com/argoproj/argo-cd/releases.atom": "already in feeds.yaml",
+		// Variants of a tracked repo's feed, or of one imported above.
+		"http://github.com/ArgoProj/argo-cd/tags.atom":       "already in feeds.yaml",
+		"https://github.com/envoyproxy/envoy/releases.atom/": "already in feeds.yaml",
+		// The HTML releases page of a repo, not its feed.
+		"https://github.com/example/tool/releases": "GitHub page, not a releases.atom or tags.atom feed",
+		"https://backstage.io/other.xml":           "project already has a blog",
+		"https://loose.example/feed.xml":           "maturity unknown",
+	} {
+		if !strings.HasPrefix(reasons[url], want) {
+			t.Errorf("skip reason for %s = %q, want %q", url, reasons[url], want)
+		}
+	}
+
+	config, err := loadConfigDoc(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tool *models.FeedSource
+	for i, f := range cfg.Feeds {
+		if f.URL == "https://github.com/example/tool/releases.atom" {
+			tool = &cfg.Feeds[i]
+		}
+	}
+	if tool == nil || !tool.Pinned || tool.Project == nil || *tool.Project != "Tool" {
+		t.Fatalf("imported feed = %+v", tool)
+	}
+	if b := cfg.Blogs[len(cfg.Blogs)-1]; b.Project != "Tool" || !b.Pinned || b.BlogURL != "https://tool.dev/blog" {
+		t.Errorf("imported blog = %+v", b)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "# Total: 5 release feeds, 2 blog feeds") || !strings.Contains(string(data), "# pinned by hand") {
+		t.Errorf("header or comments not preserved:\n%s", data)
+	}
+
+	// Landscape sync must leave pinned feeds alone even though they are not CNCF projects.
+	// Only projects already in the file, so the dry run probes nothing.
+	sync, _, err := DryRun(path, map[string]models.LandscapeProject{
+		"argoproj/argo-cd": {Name: "Argo", Status: "graduated"},
+	}, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range sync.Removed {
+		if r.OrgRepo == "example/tool" || r.OrgRepo == "project-akri/akri" {
+			t.Errorf("sync removed pinned feed %+v", r)
+		}
+	}
+	if len(sync.Removed) != 2 {
+		t.Errorf("sync removed %+v, want the unpinned akri/akri and envoyproxy/envoy", sync.Removed)
+	}
+	for _, r := range sync.BlogsRemoved {
+		if r.Name == "Tool" {
+			t.Errorf("sync removed pinned blog %+v", r)
+		}
+	}
+}
+
+func TestImportOPMLDefaultCategory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "feeds.yaml")
+	if err := os.WriteFile(path, []byte(testConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+	doc, err := opml.Parse(strings.NewReader(testOPML))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := ImportOPML(path, doc, nil, ImportOptions{Category: "sandbox", DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(result.Diff, "+    - url: https://loose.example/feed.xml\n") {
+		t.Errorf("diff missing loose blog:\n%s", result.Diff)
+	}
+	if data, _ := os.ReadFile(path); string(data) != testConfig {
+		t.Error("dry run modified the config file")
+	}
+}
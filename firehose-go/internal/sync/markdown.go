@@ -0,0 +1,105 @@
+package sync
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Markdown renders the sync result as the body of the weekly sync pull request.
+func (r *SyncResult) Markdown() string {
+	var b strings.Builder
+
+	b.WriteString("## Automated Landscape Sync\n\n")
+	b.WriteString("This PR syncs `firehose-go/config/feeds.yaml` with the current CNCF landscape.\n\n")
+
+	b.WriteString("### Changes\n")
+	fmt.Fprintf(&b, "- Release feeds: +%d added, -%d removed (total %d)\n", len(r.Added), len(r.Removed), r.Total)
+	fmt.Fprintf(&b, "- Release feeds skipped (no releases or tags): %d\n", len(r.Skipped))
+	fmt.Fprintf(&b, "- Release feeds moved (repo renamed/transferred): %d\n", len(r.Moved))
+	fmt.Fprintf(&b, "- Maturity changes: %d\n", len(r.Promoted))
+	fmt.Fprintf(&b, "- Category corrections (not announced): %d\n", len(r.Recategorized))
+	fmt.Fprintf(&b, "- Blog feeds: +%d added, -%d removed (total %d)\n", len(r.BlogsAdded), len(r.BlogsRemoved), r.BlogsTotal)
+	fmt.Fprintf(&b, "- Blog discovery failures this run: %d\n", len(r.BlogsDiscoveryFailed))
+	fmt.Fprintf(&b, "- Blog discovery skipped due to back-off: %d\n", len(r.BlogsDiscoveryDeferred))
+
+	if len(r.Added) > 0 {
+		b.WriteString("\n### Release feeds added\n\n| Project | Repo | Status | Feed |\n|---|---|---|---|\n")
+		for _, e := range r.Added {
+			fmt.Fprintf(&b, "| %s | `%s` | %s | %s |\n", cell(e.Name), e.OrgRepo, e.Status, e.FeedURL)
+		}
+	}
+	if len(r.Removed) > 0 {
+		b.WriteString("\n### Release feeds removed\n\n| Project | Repo | Status | Feed |\n|---|---|---|---|\n")
+		for _, e := range r.Removed {
+			fmt.Fprintf(&b, "| %s | `%s` | %s | %s |\n", cell(e.Name), e.OrgRepo, cell(e.Status), e.FeedURL)
+		}
+	}
+	if len(r.Moved) > 0 {
+		b.WriteString("\n### Repos moved\n\n| Project | From | To |\n|---|---|---|\n")
+		for _, e := range r.Moved {
+			fmt.Fprintf(&b, "| %s | `%s` | `%s` |\n", cell(e.Name), e.From, e.To)
+		}
+	}
+	if len(r.Promoted) > 0 {
+		b.WriteString("\n### Maturity changes\n\n| Project | From | To | Date |\n|---|---|---|---|\n")
+		for _, e := range r.Promoted {
+			fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", cell(e.Name), e.From, e.To, e.Date)
+		}
+	}
+	if len(r.Recategorized) > 0 {
+		b.WriteString("\n### Category corrections\n\n| Project | From | To |\n|---|---|---|\n")
+		for _, e := range r.Recategorized {
+			fmt.Fprintf(&b, "| %s | %s | %s |\n", cell(e.Name), cell(e.From), e.To)
+		}
+	}
+	if len(r.Skipped) > 0 {
+		b.WriteString("\n### Release feeds skipped\n\n| Project | Repo | Reason |\n|---|---|---|\n")
+		for _, e := range r.Skipped {
+			fmt.Fprintf(&b, "| %s | `%s` | %s |\n", cell(e.Name), e.OrgRepo, cell(e.Reason))
+		}
+	}
+	writeBlogTable(&b, "Blog feeds added", r.BlogsAdded)
+	writeBlogTable(&b, "Blog feeds removed", r.BlogsRemoved)
+	writeFailureTable(&b, "Blog discovery failures this run", r.BlogsDiscoveryFailed)
+	writeFailureTable(&b, "Blog discovery skipped due to back-off", r.BlogsDiscoveryDeferred)
+
+	b.WriteString("\n### What to review\n")
+	b.WriteString("- Verify added feeds are for valid CNCF projects\n")
+	b.WriteString("- Check removed feeds are no longer active CNCF projects\n")
+	b.WriteString("- Confirm no unexpected entries were removed\n")
+	b.WriteString("- Check maturity changes match the TOC announcements\n")
+
+	b.WriteString("\nGenerated by: `.github/workflows/landscape-sync.yaml`\n\n")
+	b.WriteString("Assisted-by: Automated workflow\n")
+	return b.String()
+}
+
+func writeBlogTable(b *strings.Builder, title string, entries []BlogSyncEntry) {
+	if len(entries) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "\n### %s\n\n| Project | Status | Blog | Feed | Strategy |\n|---|---|---|---|---|\n", title)
+	for _, e := range entries {
+		fmt.Fprintf(b, "| %s | %s | %s | %s | %s |\n", cell(e.Name), cell(e.Status), cell(e.BlogURL), cell(e.FeedURL), cell(e.Strategy))
+	}
+}
+
+func writeFailureTable(b *strings.Builder, title string, entries []BlogSyncEntry) {
+	if len(entries) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "\n### %s\n\n| Project | Blog | Attempts | Last strategy | Reason | Next attempt |\n|---|---|---|---|---|---|\n", title)
+	for _, e := range entries {
+		fmt.Fprintf(b, "| %s | %s | %d | %s | %s | %s |\n",
+			cell(e.Name), cell(e.BlogURL), e.Attempts, cell(e.Strategy), cell(e.Reason), cell(e.NextAttempt))
+	}
+}
+
+// cell escapes a value for use inside a Markdown table cell.
+func cell(s string) string {
+	if s == "" {
+		return "—"
+	}
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", " ")
+}
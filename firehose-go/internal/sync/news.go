@@ -0,0 +1,110 @@
+package sync
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/castrojo/firehose-go/internal/atomicfile"
+	"github.com/castrojo/firehose-go/internal/models"
+)
+
+// AnnouncementsPath is where sync records maturity-change news items.
+// The file is committed alongside feeds.yaml and merged into the news feed
+// by cmd/firehose, so the site can announce "Project X graduated".
+const AnnouncementsPath = "config/announcements.json"
+
+const landscapeSiteURL = "https://landscape.cncf.io"
+
+// PromotionNews converts maturity changes into news items, dated by the
+// landscape milestone of each promotion.
+func PromotionNews(promoted []PromotionEntry, landscapeData map[string]models.LandscapeProject, now time.Time) []models.Release {
+	var items []models.Release
+	for _, p := range promoted {
+		proj := landscapeData[p.OrgRepo]
+		link := proj.HomepageURL
+		if link == "" {
+			link = proj.RepoURL
+		}
+		if link == "" {
+			link = landscapeSiteURL
+		}
+		pubDate, err := time.Parse(time.DateOnly, p.Date)
+		if err != nil {
+			pubDate = now
+		}
+		items = append(items, models.Release{
+			ID:                 fmt.Sprintf("cncf-maturity:%s:%s", p.OrgRepo, p.To),
+			Title:              promotionTitle(p),
+			Link:               link,
+			PubDate:            pubDate.UTC(),
+			ContentSnippet:     fmt.Sprintf("%s moved from %s to %s in the CNCF.", p.Name, p.From, p.To),
+			ProjectName:        p.Name,
+			ProjectDescription: proj.Description,
+			ProjectStatus:      p.To,
+			ProjectHomepage:    proj.HomepageURL,
+			FeedURL:            landscapeSiteURL,
+			FeedTitle:          "CNCF Landscape",
+			FeedStatus:         "success",
+			FetchedAt:          now.UTC(),
+		})
+	}
+	return items
+}
+
+func promotionTitle(p PromotionEntry) string {
+	if p.To == "graduated" {
+		return p.Name + " graduated"
+	}
+	return p.Name + " moved to incubation"
+}
+
+// LoadAnnouncements reads the announcements file. A missing file is not an error.
+func LoadAnnouncements(path string) ([]models.Release, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read announcements: %w", err)
+	}
+	var items []models.Release
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, fmt.Errorf("parse announcements: %w", err)
+	}
+	return items, nil
+}
+
+// MergeAnnouncements adds items to the announcements file, replacing any
+// existing item with the same ID, and keeps the file sorted newest first.
+func MergeAnnouncements(path string, items []models.Release) error {
+	existing, err := LoadAnnouncements(path)
+	if err != nil {
+		return err
+	}
+	byID := make(map[string]int, len(existing))
+	for i, item := range existing {
+		byID[item.ID] = i
+	}
+	for _, item := range items {
+		if i, ok := byID[item.ID]; ok {
+			existing[i] = item
+			continue
+		}
+		byID[item.ID] = len(existing)
+		existing = append(existing, item)
+	}
+	sort.SliceStable(existing, func(i, j int) bool {
+		return existing[i].PubDate.After(existing[j].PubDate)
+	})
+
+	data, err := json.MarshalIndent(existing, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal announcements: %w", err)
+	}
+	return atomicfile.WriteFile(path, append(data, '\n'), 0644, atomicfile.VerifyJSON[[]models.Release]())
+}
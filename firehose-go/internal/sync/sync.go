@@ -2,27 +2,38 @@ package sync
 
 import (
 	"fmt"
+	"log"
 	"os"
+	"path/filepath"
 	"sort"
+	"strings"
 	gosync "sync"
+	"time"
 
+	"github.com/castrojo/firehose-go/internal/atomicfile"
 	"github.com/castrojo/firehose-go/internal/blog"
 	"github.com/castrojo/firehose-go/internal/feeds"
 	"github.com/castrojo/firehose-go/internal/models"
+	"github.com/castrojo/firehose-go/internal/textdiff"
 	"github.com/castrojo/firehose-go/internal/urlutil"
-	"gopkg.in/yaml.v3"
 )
 
 // SyncResult describes what the sync run did
 type SyncResult struct {
-	Added                []SyncEntry     `json:"added"`
-	Removed              []SyncEntry     `json:"removed"`
-	Total                int             `json:"total"`
-	Changed              bool            `json:"changed"`
-	BlogsAdded           []BlogSyncEntry `json:"blogsAdded"`
-	BlogsRemoved         []BlogSyncEntry `json:"blogsRemoved"`
-	BlogsDiscoveryFailed []BlogSyncEntry `json:"blogsDiscoveryFailed"`
-	BlogsTotal           int             `json:"blogsTotal"`
+	Added                []SyncEntry      `json:"added"`
+	Removed              []SyncEntry      `json:"removed"`
+	Total                int              `json:"total"`
+	Changed              bool             `json:"changed"`
+	Promoted             []PromotionEntry `json:"promoted"`      // Promotions with a landscape milestone date; announced
+	Recategorized        []PromotionEntry `json:"recategorized"` // Other category corrections; not announced
+	Skipped              []SkippedEntry   `json:"skipped"`
+	Moved                []MovedEntry     `json:"moved"`
+	BlogsAdded           []BlogSyncEntry  `json:"blogsAdded"`
+	BlogsRemoved         []BlogSyncEntry  `json:"blogsRemoved"`
+	BlogsDiscoveryFailed []BlogSyncEntry  `json:"blogsDiscoveryFailed"`
+	// Blogs not probed this run because an earlier discovery failure is backing off.
+	BlogsDiscoveryDeferred []BlogSyncEntry `json:"blogsDiscoveryDeferred"`
+	BlogsTotal             int             `json:"blogsTotal"`
 }
 
 // SyncEntry describes a single add/remove action for release feeds
@@ -33,23 +44,141 @@ type SyncEntry struct {
 	FeedURL string `json:"feedUrl"`
 }
 
-// BlogSyncEntry describes a single add/remove action for blog feeds
-type BlogSyncEntry struct {
+// SkippedEntry describes a landscape project whose feed was not added
+type SkippedEntry struct {
 	OrgRepo string `json:"orgRepo"`
 	Name    string `json:"name"`
 	Status  string `json:"status"`
-	BlogURL string `json:"blogUrl"`
-	FeedURL string `json:"feedUrl"`
+	Reason  string `json:"reason"`
+}
+
+// MovedEntry describes a release feed whose GitHub repo was renamed or transferred
+type MovedEntry struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Name   string `json:"name"`
+	OldURL string `json:"oldUrl"`
+	NewURL string `json:"newUrl"`
+}
+
+// PromotionEntry describes a project whose CNCF maturity level changed
+type PromotionEntry struct {
+	OrgRepo string `json:"orgRepo"`
+	Name    string `json:"name"`
+	From    string `json:"from"`
+	To      string `json:"to"`
+	Date    string `json:"date,omitempty"` // Landscape milestone date (YYYY-MM-DD) of a promotion
+}
+
+// BlogSyncEntry describes a single add/remove action for blog feeds
+type BlogSyncEntry struct {
+	OrgRepo  string `json:"orgRepo"`
+	Name     string `json:"name"`
+	Status   string `json:"status"`
+	BlogURL  string `json:"blogUrl"`
+	FeedURL  string `json:"feedUrl"`
+	Strategy string `json:"strategy,omitempty"` // discovery strategy that found FeedURL
+
+	// Discovery failures only: consecutive failed attempts, why, and when the blog is probed next.
+	Attempts    int    `json:"attempts,omitempty"`
+	Reason      string `json:"reason,omitempty"`
+	NextAttempt string `json:"nextAttempt,omitempty"`
+}
+
+// Options configures a sync run.
+type Options struct {
+	// Statuses are the feed results of a previous fetch; feeds that redirected to a
+	// different GitHub repo are rewritten to their new URL. May be nil.
+	Statuses []models.FeedStatus
+	// DiscoveryStatePath is the blog discovery state file used to back off
+	// blogs whose discovery keeps failing. Empty disables back-off.
+	DiscoveryStatePath string
+	// Now is the time of the run; the zero value means time.Now().
+	Now time.Time
 }
 
 // Run performs the landscape sync against feeds.yaml.
 // It reads configPath, diffs it against landscapeData, and writes the result back.
-func Run(configPath string, landscapeData map[string]models.LandscapeProject) (*SyncResult, error) {
-	config, err := feeds.LoadConfig(configPath)
+// Entries are added and removed in place so comments and ordering are preserved.
+// The blog discovery state is saved even when feeds.yaml is unchanged.
+func Run(configPath string, landscapeData map[string]models.LandscapeProject, opts Options) (*SyncResult, error) {
+	result, doc, state, err := plan(configPath, landscapeData, opts)
+	if err != nil {
+		return nil, err
+	}
+	if opts.DiscoveryStatePath != "" {
+		if err := state.Save(opts.DiscoveryStatePath); err != nil {
+			return nil, fmt.Errorf("save discovery state: %w", err)
+		}
+	}
+	if !result.Changed {
+		return result, nil
+	}
+	if err := writeConfig(configPath, doc); err != nil {
+		return nil, fmt.Errorf("write config: %w", err)
+	}
+	return result, nil
+}
+
+// DryRun computes the same changes as Run but leaves configPath and the
+// discovery state untouched. It returns the result and a unified diff of the
+// edits Run would make.
+func DryRun(configPath string, landscapeData map[string]models.LandscapeProject, opts Options) (*SyncResult, string, error) {
+	result, doc, _, err := plan(configPath, landscapeData, opts)
+	if err != nil {
+		return nil, "", err
+	}
+	if !result.Changed {
+		return result, "", nil
+	}
+	diff, err := diffConfig(configPath, doc)
+	if err != nil {
+		return nil, "", err
+	}
+	return result, diff, nil
+}
+
+// diffConfig returns a unified diff from configPath on disk to the edited doc.
+func diffConfig(configPath string, doc *configDoc) (string, error) {
+	before, err := os.ReadFile(configPath)
+	if err != nil {
+		return "", fmt.Errorf("read config: %w", err)
+	}
+	doc.setTotals()
+	after, err := doc.encode()
+	if err != nil {
+		return "", err
+	}
+	name := filepath.ToSlash(configPath)
+	return textdiff.Unified("a/"+name, "b/"+name, before, after), nil
+}
+
+// plan loads configPath, computes the sync result and applies it to the
+// returned document and discovery state without writing anything to disk.
+func plan(configPath string, landscapeData map[string]models.LandscapeProject, opts Options) (*SyncResult, *configDoc, *DiscoveryState, error) {
+	doc, err := loadConfigDoc(configPath)
 	if err != nil {
-		return nil, fmt.Errorf("load config: %w", err)
+		return nil, nil, nil, fmt.Errorf("load config: %w", err)
+	}
+	config, err := doc.decode()
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("load config: %w", err)
+	}
+	state := &DiscoveryState{Blogs: make(map[string]*DiscoveryRecord)}
+	if opts.DiscoveryStatePath != "" {
+		if state, err = LoadDiscoveryState(opts.DiscoveryStatePath); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
 	}
 
+	// Rewrite feeds whose repo moved before diffing, so a transfer is a single
+	// URL change rather than an add/remove pair.
+	moved := applyMoves(doc, config, opts.Statuses, landscapeData)
+
 	// Build set of org/repo slugs currently tracked in feeds.yaml
 	existing := make(map[string]bool)
 	for _, f := range config.Feeds {
@@ -66,6 +195,15 @@ func Run(configPath string, landscapeData map[string]models.LandscapeProject) (*
 			landscapeSet[slug] = proj
 		}
 	}
+	// Until landscape.yml catches up with a transfer, track the project under its new slug.
+	for _, m := range moved {
+		if proj, ok := landscapeSet[m.From]; ok {
+			if _, known := landscapeSet[m.To]; !known {
+				delete(landscapeSet, m.From)
+				landscapeSet[m.To] = proj
+			}
+		}
+	}
 
 	// Compute additions: in landscape but not in feeds.yaml
 	var added []SyncEntry
@@ -81,11 +219,15 @@ func Run(configPath string, landscapeData map[string]models.LandscapeProject) (*
 		}
 	}
 
-	// Compute removals: in feeds.yaml but not in landscape as a CNCF project
+	// Probe candidates so repos without releases are not added as empty feeds.
+	added, skipped := probeCandidates(added)
+
+	// Compute removals: in feeds.yaml but not in landscape as a CNCF project.
+	// Pinned feeds were added by hand and are left alone.
 	var removed []SyncEntry
 	for _, f := range config.Feeds {
 		slug := urlutil.ExtractOrgRepo(f.URL)
-		if slug == "" {
+		if slug == "" || f.Pinned {
 			continue
 		}
 		if _, inLandscape := landscapeSet[slug]; !inLandscape {
@@ -106,76 +248,265 @@ func Run(configPath string, landscapeData map[string]models.LandscapeProject) (*
 	result := &SyncResult{
 		Added:   added,
 		Removed: removed,
-		Changed: len(added) > 0 || len(removed) > 0,
-		Total:   len(config.Feeds),
+		Skipped: skipped,
+		Moved:   moved,
+		Changed: len(added) > 0 || len(removed) > 0 || len(moved) > 0,
 	}
 
-	// Build removal set for filtering
-	removeSet := make(map[string]bool)
+	// Edit the node tree in place so comments and ordering survive.
 	for _, r := range removed {
-		removeSet[r.OrgRepo] = true
-	}
-
-	// Rebuild feed list: keep existing (minus removed), append added
-	var newFeeds []models.FeedSource
-	for _, f := range config.Feeds {
-		slug := urlutil.ExtractOrgRepo(f.URL)
-		if !removeSet[slug] {
-			newFeeds = append(newFeeds, f)
-		}
+		doc.removeFeed(r.FeedURL)
 	}
 	for _, a := range added {
-		newFeeds = append(newFeeds, models.FeedSource{
+		if err := doc.addFeed(models.FeedSource{
 			URL:      a.FeedURL,
 			Category: a.Status, // required by validator; landscape is authoritative at runtime
-		})
+		}); err != nil {
+			return nil, nil, nil, err
+		}
 	}
 
-	result.Total = len(newFeeds)
+	result.Total = doc.count("feeds")
+
+	// Rewrite stale categories for projects whose maturity level changed.
+	result.Promoted, result.Recategorized = syncCategories(doc, config, landscapeSet)
+	if len(result.Promoted) > 0 || len(result.Recategorized) > 0 {
+		result.Changed = true
+	}
 
 	// Blog sync runs before early-return so it always fires.
-	result.BlogsAdded, result.BlogsRemoved, result.BlogsDiscoveryFailed = syncBlogs(config, landscapeData)
+	result.BlogsAdded, result.BlogsRemoved, result.BlogsDiscoveryFailed, result.BlogsDiscoveryDeferred =
+		syncBlogs(config, landscapeData, state, now)
 	result.BlogsTotal = len(config.Blogs) + len(result.BlogsAdded) - len(result.BlogsRemoved)
 	if len(result.BlogsAdded) > 0 || len(result.BlogsRemoved) > 0 {
 		result.Changed = true
 	}
 
 	if !result.Changed {
-		return result, nil
+		return result, doc, state, nil
 	}
 
-	// Rebuild blog list: retain existing minus removals, then append additions.
-	// Key removeBlogSet by project name (r.Name) — that is what b.Project stores.
-	var newBlogs []models.BlogSource
-	removeBlogSet := make(map[string]bool)
+	// Blog entries are keyed by project name (r.Name) — that is what b.Project stores.
 	for _, r := range result.BlogsRemoved {
-		removeBlogSet[r.Name] = true
-	}
-	for _, b := range config.Blogs {
-		if !removeBlogSet[b.Project] {
-			newBlogs = append(newBlogs, b)
-		}
+		doc.removeBlog(r.Name)
 	}
 	for _, a := range result.BlogsAdded {
-		newBlogs = append(newBlogs, models.BlogSource{
+		if err := doc.addBlog(models.BlogSource{
 			URL:      a.FeedURL,
 			Category: a.Status,
 			Project:  a.Name,
 			BlogURL:  a.BlogURL,
+		}); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	return result, doc, state, nil
+}
+
+// applyMoves rewrites release feeds whose last fetch redirected to a different
+// GitHub org/repo. config is updated in memory to match the edited document.
+func applyMoves(
+	doc *configDoc,
+	config *models.FeedConfig,
+	statuses []models.FeedStatus,
+	landscapeData map[string]models.LandscapeProject,
+) []MovedEntry {
+	finalURLs := make(map[string]string)
+	for _, st := range statuses {
+		if st.FinalURL != "" {
+			finalURLs[st.FeedURL] = st.FinalURL
+		}
+	}
+
+	var moved []MovedEntry
+	for i, f := range config.Feeds {
+		finalURL, ok := finalURLs[f.URL]
+		if !ok || f.Pinned {
+			continue
+		}
+		from := urlutil.ExtractOrgRepo(f.URL)
+		to := urlutil.ExtractOrgRepo(finalURL)
+		// GitHub slugs are case-insensitive; only a real rename or transfer counts.
+		if from == "" || to == "" || strings.EqualFold(from, to) {
+			continue
+		}
+		doc.setField("feeds", "url", f.URL, "url", finalURL)
+		config.Feeds[i].URL = finalURL
+
+		name := landscapeData[to].Name
+		if name == "" {
+			name = landscapeData[from].Name
+		}
+		log.Printf("  repo moved: %s -> %s", from, to)
+		moved = append(moved, MovedEntry{
+			From:   from,
+			To:     to,
+			Name:   name,
+			OldURL: f.URL,
+			NewURL: finalURL,
 		})
 	}
 
-	if err := writeConfig(configPath, newFeeds, newBlogs); err != nil {
-		return nil, fmt.Errorf("write config: %w", err)
+	sort.Slice(moved, func(i, j int) bool { return moved[i].From < moved[j].From })
+	return moved
+}
+
+// probeFeed is swapped out in tests to avoid network access.
+var probeFeed = feeds.ProbeFeed
+
+// probeCandidates checks each candidate's releases.atom feed before it is added.
+// Repos with no releases fall back to tags.atom; repos with neither are skipped.
+func probeCandidates(candidates []SyncEntry) (kept []SyncEntry, skipped []SkippedEntry) {
+	type probeResult struct {
+		entry  SyncEntry
+		reason string
 	}
+	resultCh := make(chan probeResult, len(candidates))
+	var wg gosync.WaitGroup
+	sem := make(chan struct{}, 10)
 
-	return result, nil
+	for _, c := range candidates {
+		wg.Add(1)
+		go func(entry SyncEntry) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			releasesCount, releasesErr := probeFeed(entry.FeedURL)
+			if releasesErr == nil && releasesCount > 0 {
+				resultCh <- probeResult{entry: entry}
+				return
+			}
+
+			tagsURL := "https://github.com/" + entry.OrgRepo + "/tags.atom"
+			tagsCount, tagsErr := probeFeed(tagsURL)
+			if tagsErr == nil && tagsCount > 0 {
+				log.Printf("  release probe: %s has no releases, using tags feed", entry.OrgRepo)
+				entry.FeedURL = tagsURL
+				resultCh <- probeResult{entry: entry}
+				return
+			}
+
+			reason := "no releases or tags published"
+			if releasesErr != nil || tagsErr != nil {
+				reason = fmt.Sprintf("releases.atom: %s; tags.atom: %s", probeOutcome(releasesErr), probeOutcome(tagsErr))
+			}
+			log.Printf("  release probe: skipping %s (%s)", entry.OrgRepo, reason)
+			resultCh <- probeResult{entry: entry, reason: reason}
+		}(c)
+	}
+	go func() { wg.Wait(); close(resultCh) }()
+
+	for res := range resultCh {
+		if res.reason == "" {
+			kept = append(kept, res.entry)
+			continue
+		}
+		skipped = append(skipped, SkippedEntry{
+			OrgRepo: res.entry.OrgRepo,
+			Name:    res.entry.Name,
+			Status:  res.entry.Status,
+			Reason:  res.reason,
+		})
+	}
+
+	sort.Slice(kept, func(i, j int) bool { return kept[i].OrgRepo < kept[j].OrgRepo })
+	sort.Slice(skipped, func(i, j int) bool { return skipped[i].OrgRepo < skipped[j].OrgRepo })
+	return kept, skipped
 }
 
+// probeOutcome describes a probe result for a skip reason.
+func probeOutcome(err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	return "empty"
+}
+
+// syncCategories updates the category of tracked release and blog feeds whose
+// landscape project status differs, with one entry per changed project. Only
+// promotions (sandbox → incubating → graduated) that the landscape dates with
+// a milestone are returned as promoted; every other mismatch is corrected and
+// returned as recategorized, so it is never announced.
+func syncCategories(
+	doc *configDoc,
+	config *models.FeedConfig,
+	landscapeSet map[string]models.LandscapeProject,
+) (promoted, recategorized []PromotionEntry) {
+	seen := make(map[string]bool)
+	record := func(slug string, proj models.LandscapeProject, from string) {
+		if seen[proj.Name] {
+			return
+		}
+		seen[proj.Name] = true
+		e := PromotionEntry{OrgRepo: slug, Name: proj.Name, From: from, To: proj.Status}
+		if e.Date = milestoneDate(proj, from); e.Date != "" {
+			promoted = append(promoted, e)
+		} else {
+			recategorized = append(recategorized, e)
+		}
+	}
+
+	for _, f := range config.Feeds {
+		slug := urlutil.ExtractOrgRepo(f.URL)
+		proj, ok := landscapeSet[slug]
+		if !ok || f.Pinned || proj.Status == f.Category {
+			continue
+		}
+		doc.setFeedCategory(f.URL, proj.Status)
+		record(slug, proj, f.Category)
+	}
+
+	// Blogs are keyed by project name rather than GitHub slug.
+	slugByName := make(map[string]string)
+	for slug, proj := range landscapeSet {
+		slugByName[proj.Name] = slug
+	}
+	for _, b := range config.Blogs {
+		slug, ok := slugByName[b.Project]
+		if !ok || b.Pinned {
+			continue
+		}
+		proj := landscapeSet[slug]
+		if proj.Status == b.Category {
+			continue
+		}
+		doc.setBlogCategory(b.Project, proj.Status)
+		record(slug, proj, b.Category)
+	}
+
+	sort.Slice(promoted, func(i, j int) bool { return promoted[i].OrgRepo < promoted[j].OrgRepo })
+	sort.Slice(recategorized, func(i, j int) bool { return recategorized[i].OrgRepo < recategorized[j].OrgRepo })
+	return promoted, recategorized
+}
+
+// maturityRank orders the CNCF maturity levels.
+var maturityRank = map[string]int{"sandbox": 1, "incubating": 2, "graduated": 3}
+
+// milestoneDate returns the landscape date on which proj reached its current
+// status, if that status is a promotion from the given one; otherwise "".
+func milestoneDate(proj models.LandscapeProject, from string) string {
+	if maturityRank[from] == 0 || maturityRank[proj.Status] <= maturityRank[from] {
+		return ""
+	}
+	switch proj.Status {
+	case "incubating":
+		return proj.Incubating
+	case "graduated":
+		return proj.Graduated
+	}
+	return ""
+}
+
+// syncBlogs discovers feeds for untracked landscape blogs and finds tracked blogs to remove.
+// Blogs whose earlier discovery failed are skipped until their back-off expires.
 func syncBlogs(
 	config *models.FeedConfig,
 	landscapeData map[string]models.LandscapeProject,
-) (added, removed, failed []BlogSyncEntry) {
+	state *DiscoveryState,
+	now time.Time,
+) (added, removed, failed, deferred []BlogSyncEntry) {
 	existing := make(map[string]models.BlogSource)
 	for _, b := range config.Blogs {
 		existing[b.Project] = b
@@ -186,6 +517,7 @@ func syncBlogs(
 		proj    models.LandscapeProject
 	}
 	var candidates []blogCandidate
+	candidateURLs := make(map[string]bool)
 	for slug, proj := range landscapeData {
 		if proj.BlogURL == "" {
 			continue
@@ -196,12 +528,18 @@ func syncBlogs(
 		if _, tracked := existing[proj.Name]; tracked {
 			continue
 		}
+		candidateURLs[proj.BlogURL] = true
+		if rec, ok := state.due(proj.BlogURL, now); !ok {
+			deferred = append(deferred, failureEntry(slug, proj, rec))
+			continue
+		}
 		candidates = append(candidates, blogCandidate{orgRepo: slug, proj: proj})
 	}
+	state.prune(candidateURLs)
 
 	type discoveryResult struct {
 		candidate blogCandidate
-		feedURL   string
+		found     blog.Result
 	}
 	resultCh := make(chan discoveryResult, len(candidates))
 	var wg gosync.WaitGroup
@@ -213,32 +551,45 @@ func syncBlogs(
 			defer wg.Done()
 			sem <- struct{}{}
 			defer func() { <-sem }()
-			resultCh <- discoveryResult{candidate: cand, feedURL: blog.DiscoverFeedURL(cand.proj.BlogURL)}
+			resultCh <- discoveryResult{candidate: cand, found: blog.Discover(cand.proj.BlogURL)}
 		}(c)
 	}
 	go func() { wg.Wait(); close(resultCh) }()
 
 	for res := range resultCh {
+		if len(res.found.Candidates) > 1 {
+			log.Printf("  blog candidates for %s:", res.candidate.proj.Name)
+			for i, c := range res.found.Candidates {
+				log.Printf("    %d. %s (%s, score %.1f, %d items, %q)", i+1, c.FeedURL, c.Strategy, c.Score, c.Items, c.Title)
+			}
+		}
 		entry := BlogSyncEntry{
-			OrgRepo: res.candidate.orgRepo,
-			Name:    res.candidate.proj.Name,
-			Status:  res.candidate.proj.Status,
-			BlogURL: res.candidate.proj.BlogURL,
-			FeedURL: res.feedURL,
+			OrgRepo:  res.candidate.orgRepo,
+			Name:     res.candidate.proj.Name,
+			Status:   res.candidate.proj.Status,
+			BlogURL:  res.candidate.proj.BlogURL,
+			FeedURL:  res.found.FeedURL,
+			Strategy: res.found.Strategy,
 		}
-		if res.feedURL != "" {
+		if res.found.FeedURL != "" {
+			state.recordSuccess(res.candidate.proj.BlogURL)
 			added = append(added, entry)
 		} else {
-			failed = append(failed, entry)
+			rec := state.recordFailure(res.candidate.proj.BlogURL, res.candidate.proj.Name, res.found, now)
+			failed = append(failed, failureEntry(res.candidate.orgRepo, res.candidate.proj, rec))
 		}
 	}
 
-	// Removals: tracked blogs whose project no longer has blog_url or CNCF status.
+	// Removals: tracked blogs whose project no longer has blog_url or CNCF status,
+	// unless they are pinned.
 	landscapeByName := make(map[string]models.LandscapeProject)
 	for _, proj := range landscapeData {
 		landscapeByName[proj.Name] = proj
 	}
 	for _, b := range config.Blogs {
+		if b.Pinned {
+			continue
+		}
 		proj, exists := landscapeByName[b.Project]
 		if !exists || proj.BlogURL == "" ||
 			(proj.Status != "graduated" && proj.Status != "incubating" && proj.Status != "sandbox") {
@@ -254,25 +605,35 @@ func syncBlogs(
 	sort.Slice(added, func(i, j int) bool { return added[i].Name < added[j].Name })
 	sort.Slice(removed, func(i, j int) bool { return removed[i].Name < removed[j].Name })
 	sort.Slice(failed, func(i, j int) bool { return failed[i].Name < failed[j].Name })
+	sort.Slice(deferred, func(i, j int) bool { return deferred[i].Name < deferred[j].Name })
 	return
 }
 
-// writeConfig writes the updated feed and blog lists back to feeds.yaml.
-func writeConfig(path string, feedSources []models.FeedSource, blogSources []models.BlogSource) error {
-	config := models.FeedConfig{
-		Feeds: feedSources,
-		Blogs: blogSources,
-	}
-	header := fmt.Sprintf(
-		"# Feed Configuration for The Firehose (Go)\n"+
-			"# Managed by landscape-sync workflow — do not edit feed URLs manually\n"+
-			"# Source of truth: https://landscape.cncf.io\n"+
-			"# Total: %d release feeds, %d blog feeds\n\n",
-		len(feedSources), len(blogSources),
-	)
-	data, err := yaml.Marshal(config)
+// failureEntry describes a blog whose discovery failed, using its back-off record.
+func failureEntry(orgRepo string, proj models.LandscapeProject, rec *DiscoveryRecord) BlogSyncEntry {
+	entry := BlogSyncEntry{
+		OrgRepo: orgRepo,
+		Name:    proj.Name,
+		Status:  proj.Status,
+		BlogURL: proj.BlogURL,
+	}
+	if rec != nil {
+		entry.Strategy = rec.LastStrategy
+		entry.Attempts = rec.Attempts
+		entry.Reason = rec.Reason
+		entry.NextAttempt = rec.NextAttempt.Format(time.RFC3339)
+	}
+	return entry
+}
+
+// writeConfig writes the edited node tree back to feeds.yaml.
+// Only the "# Total:" header line is regenerated; every other comment is kept.
+// The file is replaced atomically and only if it still parses as a feed config.
+func writeConfig(path string, doc *configDoc) error {
+	doc.setTotals()
+	data, err := doc.encode()
 	if err != nil {
-		return fmt.Errorf("marshal YAML: %w", err)
+		return err
 	}
-	return os.WriteFile(path, append([]byte(header), data...), 0644)
+	return atomicfile.WriteFile(path, data, 0644, verifyConfig)
 }
@@ -0,0 +1,246 @@
+package sync
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/castrojo/firehose-go/internal/models"
+)
+
+func TestSyncCategories(t *testing.T) {
+	doc, err := parseConfigDoc([]byte(testConfig))
+	if err != nil {
+		t.Fatalf("parseConfigDoc() error = %v", err)
+	}
+	config, err := doc.decode()
+	if err != nil {
+		t.Fatalf("decode() error = %v", err)
+	}
+
+	landscapeSet := map[string]models.LandscapeProject{
+		// A downgrade is corrected but never announced.
+		"argoproj/argo-cd": {Name: "Argo", Status: "incubating", Incubating: "2020-04-07"},
+		// Unchanged.
+		"envoyproxy/envoy": {Name: "Envoy", Status: "graduated"},
+		"akri/akri":        {Name: "Akri", Status: "incubating", Incubating: "2026-02-20"},
+		// No graduation date in the landscape: a correction, not news.
+		"backstage/backstage": {Name: "Backstage", Status: "graduated"},
+	}
+
+	promoted, recategorized := syncCategories(doc, config, landscapeSet)
+
+	want := []PromotionEntry{
+		{OrgRepo: "akri/akri", Name: "Akri", From: "sandbox", To: "incubating", Date: "2026-02-20"},
+	}
+	wantRecategorized := []PromotionEntry{
+		{OrgRepo: "argoproj/argo-cd", Name: "Argo", From: "graduated", To: "incubating"},
+		{OrgRepo: "backstage/backstage", Name: "Backstage", From: "incubating", To: "graduated"},
+	}
+	if fmt.Sprint(promoted) != fmt.Sprint(want) {
+		t.Errorf("promoted = %+v, want %+v", promoted, want)
+	}
+	if fmt.Sprint(recategorized) != fmt.Sprint(wantRecategorized) {
+		t.Errorf("recategorized = %+v, want %+v", recategorized, wantRecategorized)
+	}
+
+	updated, err := doc.decode()
+	if err != nil {
+		t.Fatalf("decode() error = %v", err)
+	}
+	if got := updated.Feeds[0].Category; got != "incubating" {
+		t.Errorf("argo feed category = %q, want incubating", got)
+	}
+	if got := updated.Feeds[2].Category; got != "incubating" {
+		t.Errorf("akri feed category = %q, want incubating", got)
+	}
+	if got := updated.Blogs[0].Category; got != "graduated" {
+		t.Errorf("backstage blog category = %q, want graduated", got)
+	}
+
+	out, err := doc.encode()
+	if err != nil {
+		t.Fatalf("encode() error = %v", err)
+	}
+	if !strings.Contains(string(out), "# Sandbox projects") {
+		t.Error("category rewrite dropped a comment")
+	}
+}
+
+func TestPromotionNews(t *testing.T) {
+	now := time.Date(2026, 3, 2, 4, 0, 0, 0, time.UTC)
+	landscapeData := map[string]models.LandscapeProject{
+		"akri/akri": {Name: "Akri", HomepageURL: "https://akri.sh"},
+	}
+	promoted := []PromotionEntry{
+		{OrgRepo: "akri/akri", Name: "Akri", From: "sandbox", To: "incubating", Date: "2026-02-20"},
+		{OrgRepo: "kubeedge/kubeedge", Name: "KubeEdge", From: "incubating", To: "graduated", Date: "2024-10-15"},
+	}
+
+	items := PromotionNews(promoted, landscapeData, now)
+	if len(items) != 2 {
+		t.Fatalf("PromotionNews() returned %d items, want 2", len(items))
+	}
+	if items[0].Title != "Akri moved to incubation" || items[0].Link != "https://akri.sh" {
+		t.Errorf("items[0] = %q %q", items[0].Title, items[0].Link)
+	}
+	if items[1].Title != "KubeEdge graduated" || items[1].Link != landscapeSiteURL {
+		t.Errorf("items[1] = %q %q", items[1].Title, items[1].Link)
+	}
+	graduated := time.Date(2024, 10, 15, 0, 0, 0, 0, time.UTC)
+	if items[1].ProjectStatus != "graduated" || !items[1].PubDate.Equal(graduated) || !items[1].FetchedAt.Equal(now) {
+		t.Errorf("items[1] status/date = %q %v", items[1].ProjectStatus, items[1].PubDate)
+	}
+}
+
+func TestProbeCandidates(t *testing.T) {
+	counts := map[string]int{
+		"https://github.com/a/released/releases.atom": 3,
+		"https://github.com/b/tagged/releases.atom":   0,
+		"https://github.com/b/tagged/tags.atom":       5,
+		"https://github.com/c/empty/releases.atom":    0,
+		"https://github.com/c/empty/tags.atom":        0,
+	}
+	orig := probeFeed
+	probeFeed = func(feedURL string) (int, error) {
+		n, ok := counts[feedURL]
+		if !ok {
+			return 0, fmt.Errorf("HTTP 404")
+		}
+		return n, nil
+	}
+	defer func() { probeFeed = orig }()
+
+	var candidates []SyncEntry
+	for _, slug := range []string{"c/empty", "d/missing", "b/tagged", "a/released"} {
+		candidates = append(candidates, SyncEntry{
+			OrgRepo: slug,
+			Name:    slug,
+			Status:  "sandbox",
+			FeedURL: "https://github.com/" + slug + "/releases.atom",
+		})
+	}
+
+	kept, skipped := probeCandidates(candidates)
+
+	if len(kept) != 2 {
+		t.Fatalf("kept = %+v, want 2 entries", kept)
+	}
+	if kept[0].FeedURL != "https://github.com/a/released/releases.atom" {
+		t.Errorf("kept[0].FeedURL = %q, want releases feed", kept[0].FeedURL)
+	}
+	if kept[1].FeedURL != "https://github.com/b/tagged/tags.atom" {
+		t.Errorf("kept[1].FeedURL = %q, want tags feed fallback", kept[1].FeedURL)
+	}
+
+	if len(skipped) != 2 {
+		t.Fatalf("skipped = %+v, want 2 entries", skipped)
+	}
+	if skipped[0].OrgRepo != "c/empty" || skipped[0].Reason != "no releases or tags published" {
+		t.Errorf("skipped[0] = %+v", skipped[0])
+	}
+	if skipped[1].OrgRepo != "d/missing" || !strings.Contains(skipped[1].Reason, "HTTP 404") {
+		t.Errorf("skipped[1] = %+v", skipped[1])
+	}
+}
+
+func TestApplyMoves(t *testing.T) {
+	doc, err := parseConfigDoc([]byte(testConfig))
+	if err != nil {
+		t.Fatalf("parseConfigDoc() error = %v", err)
+	}
+	config, err := doc.decode()
+	if err != nil {
+		t.Fatalf("decode() error = %v", err)
+	}
+
+	statuses := []models.FeedStatus{
+		{FeedURL: "https://github.com/akri/akri/releases.atom", FinalURL: "https://github.com/project-akri/akri/releases.atom"},
+		// Case-only differences are not moves.
+		{FeedURL: "https://github.com/envoyproxy/envoy/releases.atom", FinalURL: "https://github.com/EnvoyProxy/envoy/releases.atom"},
+		{FeedURL: "https://github.com/argoproj/argo-cd/releases.atom"},
+	}
+	landscapeData := map[string]models.LandscapeProject{
+		"project-akri/akri": {Name: "Akri", Status: "sandbox"},
+	}
+
+	moved := applyMoves(doc, config, statuses, landscapeData)
+
+	if len(moved) != 1 {
+		t.Fatalf("applyMoves() = %+v, want 1 entry", moved)
+	}
+	want := MovedEntry{
+		From:   "akri/akri",
+		To:     "project-akri/akri",
+		Name:   "Akri",
+		OldURL: "https://github.com/akri/akri/releases.atom",
+		NewURL: "https://github.com/project-akri/akri/releases.atom",
+	}
+	if moved[0] != want {
+		t.Errorf("moved[0] = %+v, want %+v", moved[0], want)
+	}
+	if config.Feeds[2].URL != want.NewURL {
+		t.Errorf("in-memory config URL = %q, want %q", config.Feeds[2].URL, want.NewURL)
+	}
+
+	updated, err := doc.decode()
+	if err != nil {
+		t.Fatalf("decode() error = %v", err)
+	}
+	if updated.Feeds[2].URL != want.NewURL {
+		t.Errorf("document URL = %q, want %q", updated.Feeds[2].URL, want.NewURL)
+	}
+	if updated.Feeds[1].URL != "https://github.com/envoyproxy/envoy/releases.atom" {
+		t.Errorf("case-only redirect rewrote URL to %q", updated.Feeds[1].URL)
+	}
+}
+
+func TestDryRunLeavesConfigUntouched(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "feeds.yaml")
+	if err := os.WriteFile(path, []byte(testConfig), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Argo CD and Envoy stay; Akri has left the landscape and Backstage's blog_url is gone.
+	landscapeData := map[string]models.LandscapeProject{
+		"argoproj/argo-cd": {Name: "Argo", Status: "graduated"},
+		"envoyproxy/envoy": {Name: "Envoy", Status: "graduated"},
+	}
+
+	result, diff, err := DryRun(path, landscapeData, Options{})
+	if err != nil {
+		t.Fatalf("DryRun() error = %v", err)
+	}
+	if !result.Changed || len(result.Removed) != 1 || len(result.BlogsRemoved) != 1 {
+		t.Fatalf("DryRun() result = %+v", result)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != testConfig {
+		t.Error("DryRun() modified the config file")
+	}
+
+	for _, want := range []string{
+		"-# Total: 3 release feeds, 1 blog feeds",
+		"+# Total: 2 release feeds, 0 blog feeds",
+		"-    - url: https://github.com/akri/akri/releases.atom",
+		"-    - url: https://backstage.io/blog/rss.xml",
+	} {
+		if !strings.Contains(diff, want+"\n") {
+			t.Errorf("diff missing %q:\n%s", want, diff)
+		}
+	}
+
+	body := result.Markdown()
+	for _, want := range []string{"## Automated Landscape Sync", "### Release feeds removed", "`akri/akri`", "### Blog feeds removed"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Markdown() missing %q", want)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package syndicate
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strconv"
+	"time"
+)
+
+// Atom 1.0 (RFC 4287) document structure.
+type atomFeed struct {
+	XMLName   xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID        string      `xml:"id"`
+	Title     string      `xml:"title"`
+	Subtitle  string      `xml:"subtitle,omitempty"`
+	Updated   string      `xml:"updated"`
+	Links     []atomLink  `xml:"link"`
+	Author    atomPerson  `xml:"author"`
+	Generator string      `xml:"generator"`
+	Entries   []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href   string `xml:"href,attr"`
+	Rel    string `xml:"rel,attr,omitempty"`
+	Type   string `xml:"type,attr,omitempty"`
+	Length string `xml:"length,attr,omitempty"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomText struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Updated    string         `xml:"updated"`
+	Published  string         `xml:"published"`
+	Links      []atomLink     `xml:"link"`
+	Categories []atomCategory `xml:"category"`
+	Summary    *atomText      `xml:"summary,omitempty"`
+	Content    *atomText      `xml:"content,omitempty"`
+}
+
+func encodeAtom(f *Feed, site string) ([]byte, error) {
+	doc := atomFeed{
+		ID:       f.url(site, ".atom"),
+		Title:    f.Title,
+		Subtitle: f.Description,
+		Updated:  f.Updated.UTC().Format(time.RFC3339),
+		Links: []atomLink{
+			{Href: f.url(site, ".atom"), Rel: "self", Type: "application/atom+xml"},
+			{Href: site + f.HomePage, Rel: "alternate", Type: "text/html"},
+		},
+		// Atom requires an author for entries without one; items carry none.
+		Author:    atomPerson{Name: "The Firehose"},
+		Generator: "firehose-go",
+	}
+	for _, it := range f.Items {
+		date := it.PubDate.UTC().Format(time.RFC3339)
+		e := atomEntry{
+			ID:        it.id(),
+			Title:     it.title(),
+			Updated:   date,
+			Published: date,
+			Links:     []atomLink{{Href: it.Link, Rel: "alternate", Type: "text/html"}},
+		}
+		for _, enc := range it.Enclosures {
+			link := atomLink{Href: enc.URL, Rel: "enclosure", Type: enc.Type}
+			if enc.Length > 0 {
+				link.Length = strconv.FormatInt(enc.Length, 10)
+			}
+			e.Links = append(e.Links, link)
+		}
+		for _, c := range it.categories() {
+			e.Categories = append(e.Categories, atomCategory{Term: c})
+		}
+		// The snippet is plain text, cut from the feed's description.
+		if it.ContentSnippet != "" {
+			e.Summary = &atomText{Type: "text", Body: it.ContentSnippet}
+		}
+		if it.Content != "" {
+			e.Content = &atomText{Type: "html", Body: it.Content}
+		}
+		doc.Entries = append(doc.Entries, e)
+	}
+	return marshalXML(doc)
+}
+
+// marshalXML encodes v as an indented XML document with a declaration.
+func marshalXML(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,83 @@
+package syndicate
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+)
+
+// JSON Feed 1.1 (https://jsonfeed.org/version/1.1) document structure.
+type jsonFeed struct {
+	Version     string         `json:"version"`
+	Title       string         `json:"title"`
+	HomePageURL string         `json:"home_page_url"`
+	FeedURL     string         `json:"feed_url"`
+	Description string         `json:"description,omitempty"`
+	Language    string         `json:"language"`
+	Items       []jsonFeedItem `json:"items"`
+}
+
+type jsonFeedItem struct {
+	ID            string               `json:"id"`
+	URL           string               `json:"url"`
+	Title         string               `json:"title"`
+	ContentHTML   string               `json:"content_html,omitempty"`
+	ContentText   string               `json:"content_text,omitempty"`
+	Summary       string               `json:"summary,omitempty"`
+	DatePublished string               `json:"date_published"`
+	DateModified  string               `json:"date_modified"`
+	Tags          []string             `json:"tags"`
+	Attachments   []jsonFeedAttachment `json:"attachments,omitempty"`
+}
+
+type jsonFeedAttachment struct {
+	URL         string `json:"url"`
+	MIMEType    string `json:"mime_type"`
+	SizeInBytes int64  `json:"size_in_bytes,omitempty"`
+}
+
+func encodeJSONFeed(f *Feed, site string) ([]byte, error) {
+	doc := jsonFeed{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       f.Title,
+		HomePageURL: site + f.HomePage,
+		FeedURL:     f.url(site, ".json"),
+		Description: f.Description,
+		Language:    "en-US",
+		Items:       []jsonFeedItem{},
+	}
+	for _, it := range f.Items {
+		date := it.PubDate.UTC().Format(time.RFC3339)
+		item := jsonFeedItem{
+			ID:            it.id(),
+			URL:           it.Link,
+			Title:         it.title(),
+			ContentHTML:   it.Content,
+			Summary:       it.ContentSnippet,
+			DatePublished: date,
+			DateModified:  date,
+			Tags:          it.categories(),
+		}
+		// Items need content_html or content_text; fall back to the summary,
+		// then to the title.
+		if item.ContentHTML == "" {
+			item.ContentHTML = it.ContentSnippet
+		}
+		if item.ContentHTML == "" {
+			item.ContentText = item.Title
+		}
+		for _, enc := range it.Enclosures {
+			item.Attachments = append(item.Attachments, jsonFeedAttachment{URL: enc.URL, MIMEType: mimeType(enc.Type), SizeInBytes: enc.Length})
+		}
+		doc.Items = append(doc.Items, item)
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(doc); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,100 @@
+package syndicate
+
+import (
+	"encoding/xml"
+	"strconv"
+	"time"
+)
+
+// RSS 2.0 document structure, with atom:link for the self reference.
+type rssDoc struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	AtomNS  string     `xml:"xmlns:atom,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string      `xml:"title"`
+	Link          string      `xml:"link"`
+	Description   string      `xml:"description"`
+	AtomLink      rssAtomLink `xml:"atom:link"`
+	Language      string      `xml:"language"`
+	PubDate       string      `xml:"pubDate"`
+	LastBuildDate string      `xml:"lastBuildDate"`
+	Generator     string      `xml:"generator"`
+	Items         []rssItem   `xml:"item"`
+}
+
+type rssAtomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type rssEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Length string `xml:"length,attr"`
+	Type   string `xml:"type,attr"`
+}
+
+type rssItem struct {
+	Title       string        `xml:"title"`
+	Link        string        `xml:"link"`
+	GUID        rssGUID       `xml:"guid"`
+	PubDate     string        `xml:"pubDate"`
+	Categories  []string      `xml:"category"`
+	Description string        `xml:"description,omitempty"`
+	Enclosure   *rssEnclosure `xml:"enclosure,omitempty"`
+}
+
+func encodeRSS(f *Feed, site string) ([]byte, error) {
+	updated := f.Updated.UTC().Format(time.RFC1123Z)
+	doc := rssDoc{
+		Version: "2.0",
+		AtomNS:  "http://www.w3.org/2005/Atom",
+		Channel: rssChannel{
+			Title:         f.Title,
+			Link:          site + f.HomePage,
+			Description:   f.Description,
+			AtomLink:      rssAtomLink{Href: f.url(site, ".rss"), Rel: "self", Type: "application/rss+xml"},
+			Language:      "en-us",
+			PubDate:       updated,
+			LastBuildDate: updated,
+			Generator:     "firehose-go",
+		},
+	}
+	for _, it := range f.Items {
+		item := rssItem{
+			Title:      it.title(),
+			Link:       it.Link,
+			GUID:       rssGUID{IsPermaLink: it.id() == it.Link, Value: it.id()},
+			PubDate:    it.PubDate.UTC().Format(time.RFC1123Z),
+			Categories: it.categories(),
+		}
+		item.Description = it.Content
+		if item.Description == "" {
+			item.Description = it.ContentSnippet
+		}
+		// RSS 2.0 allows a single enclosure, with length and type required.
+		if len(it.Enclosures) > 0 {
+			enc := it.Enclosures[0]
+			item.Enclosure = &rssEnclosure{URL: enc.URL, Length: strconv.FormatInt(enc.Length, 10), Type: mimeType(enc.Type)}
+		}
+		doc.Channel.Items = append(doc.Channel.Items, item)
+	}
+	return marshalXML(doc)
+}
+
+// mimeType returns t, or the generic binary type when the feed gave none.
+func mimeType(t string) string {
+	if t == "" {
+		return "application/octet-stream"
+	}
+	return t
+}
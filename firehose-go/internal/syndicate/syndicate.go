@@ -0,0 +1,233 @@
+// Package syndicate writes the aggregated output as Atom 1.0, RSS 2.0 and
+// JSON Feed 1.1 files: one overall release feed, one per maturity tier, one
+// per project and one for news.
+package syndicate
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/castrojo/firehose-go/internal/atomicfile"
+	"github.com/castrojo/firehose-go/internal/models"
+)
+
+// DefaultSiteURL is the public site root used for feed links when
+// output.site_url is not set.
+const DefaultSiteURL = "https://castrojo.github.io/firehose/"
+
+// PublicDir is the directory under the site root that feeds are served from;
+// Write's dir must be published there.
+const PublicDir = "feeds"
+
+// DefaultItems is the number of most recent items kept per feed.
+const DefaultItems = 500
+
+// Tiers are the CNCF maturity levels that get a feed of their own.
+var Tiers = []string{"graduated", "incubating", "sandbox"}
+
+// Formats maps file extensions to the encoder for that format.
+var Formats = map[string]func(f *Feed, site string) ([]byte, error){
+	".atom": encodeAtom,
+	".rss":  encodeRSS,
+	".json": encodeJSONFeed,
+}
+
+// Feed is one feed before encoding.
+type Feed struct {
+	Path        string // Relative to the feeds directory, without extension, e.g. "tiers/graduated"
+	Title       string
+	Description string
+	HomePage    string // Site page the feed mirrors, relative to the site root
+	Updated     time.Time
+	Items       []Item
+}
+
+// Item is a release or news post in a feed.
+type Item struct {
+	models.Release
+	News bool
+}
+
+// title is the item title as the site's feeds show it: the project name
+// first, so items from different projects can be told apart in a reader.
+func (it Item) title() string {
+	project := it.project()
+	switch {
+	case project == "":
+		return it.Title
+	case it.News:
+		return project + " — " + it.Title
+	default:
+		return project + " " + it.Title
+	}
+}
+
+func (it Item) project() string {
+	if it.ProjectName != "" {
+		return it.ProjectName
+	}
+	return it.FeedTitle
+}
+
+// categories returns the project, maturity tier and kind of the item.
+func (it Item) categories() []string {
+	var cats []string
+	if p := it.project(); p != "" {
+		cats = append(cats, p)
+	}
+	if it.ProjectStatus != "" {
+		cats = append(cats, it.ProjectStatus)
+	}
+	if it.News {
+		return append(cats, "news")
+	}
+	return append(cats, "release")
+}
+
+// id is a stable, globally unique item ID. Atom requires an IRI, so opaque
+// GUIDs fall back to the item link.
+func (it Item) id() string {
+	if strings.Contains(it.ID, ":") {
+		return it.ID
+	}
+	return it.Link
+}
+
+// Build groups the output into feeds, newest items first, keeping at most
+// limit items per feed. Releases from failed feeds are left out.
+func Build(o *models.OutputData, limit int) []*Feed {
+	if limit <= 0 {
+		limit = DefaultItems
+	}
+	generated, _ := time.Parse(time.RFC3339, o.Metadata.GeneratedAt)
+
+	all := &Feed{
+		Path:        "all",
+		Title:       "CNCF Project Releases",
+		Description: "Releases from CNCF projects.",
+	}
+	news := &Feed{
+		Path:        "news",
+		Title:       "CNCF Project News",
+		Description: "Blog posts from CNCF projects.",
+		HomePage:    "news/",
+	}
+	tiers := make(map[string]*Feed, len(Tiers))
+	for _, tier := range Tiers {
+		tiers[tier] = &Feed{
+			Path:        "tiers/" + tier,
+			Title:       "CNCF " + strings.ToUpper(tier[:1]) + tier[1:] + " Project Releases",
+			Description: "Releases from CNCF " + tier + " projects.",
+		}
+	}
+	projects := make(map[string]*Feed)
+
+	add := func(rel models.Release, isNews bool) {
+		if rel.FeedStatus == "error" {
+			return
+		}
+		item := Item{Release: rel, News: isNews}
+		if isNews {
+			news.Items = append(news.Items, item)
+		} else {
+			all.Items = append(all.Items, item)
+			if f, ok := tiers[rel.ProjectStatus]; ok {
+				f.Items = append(f.Items, item)
+			}
+		}
+		if slug := models.Slug(rel.ProjectName); slug != "" {
+			f, ok := projects[slug]
+			if !ok {
+				f = &Feed{
+					Path:        "projects/" + slug,
+					Title:       rel.ProjectName + " Releases and News",
+					Description: "Releases and blog posts from " + rel.ProjectName + ".",
+				}
+				projects[slug] = f
+			}
+			f.Items = append(f.Items, item)
+		}
+	}
+	for _, rel := range o.Releases {
+		add(rel, false)
+	}
+	for _, rel := range o.News {
+		add(rel, true)
+	}
+
+	feeds := []*Feed{all, news}
+	for _, tier := range Tiers {
+		feeds = append(feeds, tiers[tier])
+	}
+	slugs := make([]string, 0, len(projects))
+	for slug := range projects {
+		slugs = append(slugs, slug)
+	}
+	sort.Strings(slugs)
+	for _, slug := range slugs {
+		feeds = append(feeds, projects[slug])
+	}
+
+	for _, f := range feeds {
+		sort.SliceStable(f.Items, func(i, j int) bool { return f.Items[i].PubDate.After(f.Items[j].PubDate) })
+		if len(f.Items) > limit {
+			f.Items = f.Items[:limit]
+		}
+		// The newest item dates the feed, so readers only see it change when
+		// there is something new; empty feeds fall back to the build time.
+		f.Updated = generated
+		if len(f.Items) > 0 {
+			f.Updated = f.Items[0].PubDate
+		}
+	}
+	return feeds
+}
+
+// Write encodes every feed in every format under dir and removes feed files
+// left over from earlier runs (e.g. for projects no longer tracked). It
+// returns the number of files written.
+func Write(dir, site string, feeds []*Feed) (int, error) {
+	if site == "" {
+		site = DefaultSiteURL
+	}
+	site = strings.TrimSuffix(site, "/") + "/"
+
+	written := make(map[string]bool)
+	for _, f := range feeds {
+		for ext, encode := range Formats {
+			data, err := encode(f, site)
+			if err != nil {
+				return 0, fmt.Errorf("encode %s%s: %w", f.Path, ext, err)
+			}
+			path := filepath.Join(dir, filepath.FromSlash(f.Path)+ext)
+			if err := atomicfile.WriteFile(path, data, 0644, nil); err != nil {
+				return 0, fmt.Errorf("write %s%s: %w", f.Path, ext, err)
+			}
+			written[path] = true
+		}
+	}
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || written[path] {
+			return err
+		}
+		if _, ok := Formats[filepath.Ext(path)]; ok {
+			return os.Remove(path)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("remove stale feeds: %w", err)
+	}
+	return len(written), nil
+}
+
+// url returns the public URL of a feed file.
+func (f *Feed) url(site, ext string) string {
+	return site + PublicDir + "/" + f.Path + ext
+}
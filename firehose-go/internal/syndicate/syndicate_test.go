@@ -0,0 +1,201 @@
+package syndicate
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/castrojo/firehose-go/internal/models"
+)
+
+var (
+	older = time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	newer = time.Date(2026, 2, 1, 9, 30, 0, 0, time.UTC)
+)
+
+func testOutput() *models.OutputData {
+	return &models.OutputData{
+		Metadata: models.Metadata{GeneratedAt: "2026-02-02T00:00:00Z"},
+		Releases: []models.Release{
+			{ID: "tag:github.com,2008:Repository/1/v1.30.0", Title: "v1.30.0", Link: "https://github.com/kubernetes/kubernetes/releases/tag/v1.30.0",
+				PubDate: older, Content: "<p>Notes &amp; fixes</p>", ProjectName: "Kubernetes", ProjectStatus: "graduated", FeedStatus: "success"},
+			{ID: "tag:github.com,2008:Repository/2/v0.5.0", Title: "v0.5.0", Link: "https://github.com/example/tool/releases/tag/v0.5.0",
+				PubDate: newer, ProjectName: "Tool", ProjectStatus: "sandbox", FeedStatus: "success"},
+			{ID: "broken", Title: "x", Link: "https://example.com/x", PubDate: newer, FeedStatus: "error"},
+		},
+		News: []models.Release{
+			{ID: "post-1", Title: "Kubernetes podcast", Link: "https://kubernetes.io/blog/podcast/", PubDate: newer,
+				ProjectName: "Kubernetes", ProjectStatus: "graduated", FeedStatus: "success", ContentSnippet: "Listen now",
+				Enclosures: []models.Enclosure{{URL: "https://kubernetes.io/podcast.mp3", Type: "audio/mpeg", Length: 1234}}},
+		},
+	}
+}
+
+func find(feeds []*Feed, path string) *Feed {
+	for _, f := range feeds {
+		if f.Path == path {
+			return f
+		}
+	}
+	return nil
+}
+
+func TestBuild(t *testing.T) {
+	feeds := Build(testOutput(), 0)
+
+	tests := []struct {
+		path    string
+		items   int
+		first   string
+		updated time.Time
+	}{
+		{"all", 2, "v0.5.0", newer},
+		{"news", 1, "Kubernetes podcast", newer},
+		{"tiers/graduated", 1, "v1.30.0", older},
+		{"tiers/incubating", 0, "", time.Date(2026, 2, 2, 0, 0, 0, 0, time.UTC)},
+		{"tiers/sandbox", 1, "v0.5.0", newer},
+		{"projects/kubernetes", 2, "Kubernetes podcast", newer},
+		{"projects/tool", 1, "v0.5.0", newer},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			f := find(feeds, tt.path)
+			if f == nil {
+				t.Fatal("feed missing")
+			}
+			if len(f.Items) != tt.items {
+				t.Fatalf("items = %d, want %d", len(f.Items), tt.items)
+			}
+			if tt.items > 0 && f.Items[0].Title != tt.first {
+				t.Errorf("first item = %q, want %q", f.Items[0].Title, tt.first)
+			}
+			if !f.Updated.Equal(tt.updated) {
+				t.Errorf("updated = %s, want %s", f.Updated, tt.updated)
+			}
+		})
+	}
+	if len(feeds) != 7 {
+		t.Errorf("got %d feeds, want 7", len(feeds))
+	}
+
+	if all := find(Build(testOutput(), 1), "all"); len(all.Items) != 1 {
+		t.Errorf("limit 1 kept %d items", len(all.Items))
+	}
+}
+
+func TestEncoders(t *testing.T) {
+	f := find(Build(testOutput(), 0), "projects/kubernetes")
+	site := "https://example.org/firehose/"
+
+	t.Run("atom", func(t *testing.T) {
+		data, err := encodeAtom(f, site)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var doc atomFeed
+		if err := xml.Unmarshal(data, &doc); err != nil {
+			t.Fatalf("invalid XML: %v\n%s", err, data)
+		}
+		if doc.Updated != "2026-02-01T09:30:00Z" || len(doc.Entries) != 2 {
+			t.Fatalf("updated %q, %d entries", doc.Updated, len(doc.Entries))
+		}
+		podcast := doc.Entries[0]
+		if podcast.ID != "https://kubernetes.io/blog/podcast/" {
+			t.Errorf("opaque GUID should fall back to link, got %q", podcast.ID)
+		}
+		if podcast.Title != "Kubernetes — Kubernetes podcast" {
+			t.Errorf("title = %q", podcast.Title)
+		}
+		if len(podcast.Links) != 2 || podcast.Links[1].Rel != "enclosure" || podcast.Links[1].Length != "1234" {
+			t.Errorf("links = %+v", podcast.Links)
+		}
+		if got := podcast.Summary; got == nil || got.Type != "text" || got.Body != "Listen now" {
+			t.Errorf("summary = %+v, want plain text", got)
+		}
+		if got := doc.Entries[1].Content; got == nil || got.Body != "<p>Notes &amp; fixes</p>" {
+			t.Errorf("content = %+v", got)
+		}
+		if !strings.Contains(string(data), `<category term="graduated"></category>`) {
+			t.Errorf("missing tier category:\n%s", data)
+		}
+	})
+
+	t.Run("rss", func(t *testing.T) {
+		data, err := encodeRSS(f, site)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var doc rssDoc
+		if err := xml.Unmarshal(data, &doc); err != nil {
+			t.Fatalf("invalid XML: %v\n%s", err, data)
+		}
+		for _, want := range []string{
+			`xmlns:atom="http://www.w3.org/2005/Atom"`,
+			`<atom:link href="https://example.org/firehose/feeds/projects/kubernetes.rss" rel="self" type="application/rss+xml"></atom:link>`,
+			`<lastBuildDate>Sun, 01 Feb 2026 09:30:00 +0000</lastBuildDate>`,
+			`<enclosure url="https://kubernetes.io/podcast.mp3" length="1234" type="audio/mpeg"></enclosure>`,
+			`<guid isPermaLink="true">https://kubernetes.io/blog/podcast/</guid>`,
+			`<guid isPermaLink="false">tag:github.com,2008:Repository/1/v1.30.0</guid>`,
+		} {
+			if !strings.Contains(string(data), want) {
+				t.Errorf("missing %s in:\n%s", want, data)
+			}
+		}
+	})
+
+	t.Run("json feed", func(t *testing.T) {
+		data, err := encodeJSONFeed(f, site)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var doc jsonFeed
+		if err := json.Unmarshal(data, &doc); err != nil {
+			t.Fatal(err)
+		}
+		if doc.Version != "https://jsonfeed.org/version/1.1" || doc.FeedURL != site+"feeds/projects/kubernetes.json" {
+			t.Errorf("header = %+v", doc)
+		}
+		podcast := doc.Items[0]
+		if podcast.ContentHTML != "Listen now" || len(podcast.Attachments) != 1 || podcast.Attachments[0].MIMEType != "audio/mpeg" {
+			t.Errorf("item = %+v", podcast)
+		}
+		if strings.Join(podcast.Tags, ",") != "Kubernetes,graduated,news" {
+			t.Errorf("tags = %v", podcast.Tags)
+		}
+	})
+}
+
+func TestWriteRemovesStaleFeeds(t *testing.T) {
+	dir := t.TempDir()
+	stale := filepath.Join(dir, "projects", "gone.atom")
+	keep := filepath.Join(dir, "README.txt")
+	for _, path := range []string{stale, keep} {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("old"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	n, err := Write(dir, "", Build(testOutput(), 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 7*len(Formats) {
+		t.Errorf("wrote %d files, want %d", n, 7*len(Formats))
+	}
+	if _, err := os.Stat(filepath.Join(dir, "tiers", "graduated.rss")); err != nil {
+		t.Errorf("tier feed not written: %v", err)
+	}
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Errorf("stale feed kept: %v", err)
+	}
+	if _, err := os.Stat(keep); err != nil {
+		t.Errorf("unrelated file removed: %v", err)
+	}
+}
@@ -0,0 +1,186 @@
+// Package validate enforces the `validate` struct tags on the pipeline's models.
+//
+// It interprets the small subset of rules the models use:
+//
+//	required   the field must not be its zero value
+//	omitempty  skip the remaining rules when the field is empty
+//	url        an absolute URL with a scheme and host
+//	oneof=a b  the value must be one of the space-separated options
+//
+// Nested structs and slices of structs are checked recursively. Field names in
+// errors use the JSON names, so they match the output.
+package validate
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+// FieldError is one failed rule.
+type FieldError struct {
+	Field string // JSON path, e.g. "warnings[0].code"
+	Rule  string // e.g. "required", "oneof=success error"
+	Value string
+}
+
+func (e FieldError) Error() string {
+	if e.Value == "" {
+		return fmt.Sprintf("%s: failed %q", e.Field, e.Rule)
+	}
+	return fmt.Sprintf("%s: %q failed %q", e.Field, e.Value, e.Rule)
+}
+
+// Struct checks v, a struct or pointer to struct, and returns every failed rule.
+func Struct(v any) []FieldError {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+	var errs []FieldError
+	checkStruct(rv, "", &errs)
+	return errs
+}
+
+func checkStruct(rv reflect.Value, prefix string, errs *[]FieldError) {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := prefix + fieldName(field)
+		value := rv.Field(i)
+
+		if tag, ok := field.Tag.Lookup("validate"); ok {
+			checkField(value, name, tag, errs)
+		}
+		descend(value, name, errs)
+	}
+}
+
+// descend checks nested structs and slices of structs.
+func descend(value reflect.Value, name string, errs *[]FieldError) {
+	switch value.Kind() {
+	case reflect.Pointer:
+		if !value.IsNil() {
+			descend(value.Elem(), name, errs)
+		}
+	case reflect.Struct:
+		if value.Type().PkgPath() != "" && value.Type().PkgPath() != "time" {
+			checkStruct(value, name+".", errs)
+		}
+	case reflect.Slice, reflect.Array:
+		for j := 0; j < value.Len(); j++ {
+			elem := value.Index(j)
+			if elem.Kind() == reflect.Struct || elem.Kind() == reflect.Pointer {
+				descend(elem, fmt.Sprintf("%s[%d]", name, j), errs)
+			}
+		}
+	}
+}
+
+func checkField(value reflect.Value, name, tag string, errs *[]FieldError) {
+	for value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			break
+		}
+		value = value.Elem()
+	}
+	empty := value.IsZero()
+
+	for _, rule := range strings.Split(tag, ",") {
+		rule = strings.TrimSpace(rule)
+		switch {
+		case rule == "":
+		case rule == "omitempty":
+			if empty {
+				return
+			}
+		case rule == "required":
+			if empty {
+				*errs = append(*errs, FieldError{Field: name, Rule: rule})
+				return // further rules would only repeat the problem
+			}
+		case rule == "url":
+			if s := stringValue(value); !isURL(s) {
+				*errs = append(*errs, FieldError{Field: name, Rule: rule, Value: s})
+			}
+		case strings.HasPrefix(rule, "oneof="):
+			s := stringValue(value)
+			options := strings.Fields(strings.TrimPrefix(rule, "oneof="))
+			if !contains(options, s) {
+				*errs = append(*errs, FieldError{Field: name, Rule: rule, Value: s})
+			}
+		default:
+			*errs = append(*errs, FieldError{Field: name, Rule: "unsupported rule " + rule})
+		}
+	}
+}
+
+// isURL accepts absolute URLs with a scheme and host, e.g. https://example.com/x.
+func isURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && u.Scheme != "" && u.Host != ""
+}
+
+func stringValue(value reflect.Value) string {
+	if value.Kind() == reflect.String {
+		return value.String()
+	}
+	return fmt.Sprint(value.Interface())
+}
+
+func contains(options []string, s string) bool {
+	for _, o := range options {
+		if o == s {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldName returns the JSON (or YAML) name of a field, falling back to its Go name.
+func fieldName(field reflect.StructField) string {
+	for _, key := range []string{"json", "yaml"} {
+		if tag := field.Tag.Get(key); tag != "" {
+			if name, _, _ := strings.Cut(tag, ","); name != "" && name != "-" {
+				return name
+			}
+		}
+	}
+	return field.Name
+}
+
+// Invalid is a record that failed validation.
+type Invalid struct {
+	Kind   string   `json:"kind"` // "release", "news", "feed" or "source"
+	Record any      `json:"record"`
+	Errors []string `json:"errors"`
+}
+
+// Filter splits records into the valid ones and the invalid ones, keeping order.
+func Filter[T any](kind string, records []T) ([]T, []Invalid) {
+	valid := make([]T, 0, len(records))
+	var invalid []Invalid
+	for _, r := range records {
+		errs := Struct(r)
+		if len(errs) == 0 {
+			valid = append(valid, r)
+			continue
+		}
+		messages := make([]string, len(errs))
+		for i, e := range errs {
+			messages[i] = e.Error()
+		}
+		invalid = append(invalid, Invalid{Kind: kind, Record: r, Errors: messages})
+	}
+	return valid, invalid
+}
@@ -0,0 +1,113 @@
+package validate
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/castrojo/firehose-go/internal/models"
+)
+
+func validRelease() models.Release {
+	return models.Release{
+		ID:         "tag:github.com,2008:Repository/1/v1.0.0",
+		Title:      "v1.0.0",
+		Link:       "https://github.com/example/example/releases/tag/v1.0.0",
+		PubDate:    time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		FeedURL:    "https://github.com/example/example/releases.atom",
+		FeedStatus: "success",
+		FetchedAt:  time.Date(2026, 3, 1, 6, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestStruct(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(r *models.Release)
+		want   []string // "field rule" pairs
+	}{
+		{
+			name:   "valid release",
+			mutate: func(r *models.Release) {},
+		},
+		{
+			name:   "empty link",
+			mutate: func(r *models.Release) { r.Link = "" },
+			want:   []string{"link required"},
+		},
+		{
+			name:   "relative link",
+			mutate: func(r *models.Release) { r.Link = "/releases/tag/v1" },
+			want:   []string{"link url"},
+		},
+		{
+			name:   "bogus feed status",
+			mutate: func(r *models.Release) { r.FeedStatus = "pending" },
+			want:   []string{"feedStatus oneof=success error"},
+		},
+		{
+			name:   "zero pub date",
+			mutate: func(r *models.Release) { r.PubDate = time.Time{} },
+			want:   []string{"pubDate required"},
+		},
+		{
+			name:   "omitempty skips empty optional fields",
+			mutate: func(r *models.Release) { r.ProjectStatus = ""; r.ProjectHomepage = "" },
+		},
+		{
+			name:   "omitempty still checks set fields",
+			mutate: func(r *models.Release) { r.ProjectStatus = "archived"; r.ProjectHomepage = "example.com" },
+			want:   []string{"projectStatus oneof=graduated incubating sandbox", "projectHomepage url"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := validRelease()
+			tt.mutate(&r)
+			var got []string
+			for _, e := range Struct(r) {
+				got = append(got, e.Field+" "+e.Rule)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("Struct() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStructNested(t *testing.T) {
+	status := models.FeedStatus{
+		FeedURL:   "https://example.io/feed",
+		Status:    "success",
+		FetchedAt: "2026-03-01T06:00:00Z",
+		Duration:  "1s",
+		Warnings:  []models.FeedWarning{{Code: "missing-date", Count: 1}, {Count: 2}},
+	}
+	errs := Struct(&status)
+	if len(errs) != 1 || errs[0].Field != "warnings[1].code" || errs[0].Rule != "required" {
+		t.Errorf("Struct() = %v, want warnings[1].code required", errs)
+	}
+}
+
+func TestStructSource(t *testing.T) {
+	project := "Example"
+	errs := Struct(models.FeedSource{URL: "not a url", Category: "archived", Project: &project})
+	if len(errs) != 2 || errs[0].Field != "url" || errs[1].Field != "category" {
+		t.Errorf("Struct() = %v, want url and category errors", errs)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	bad := validRelease()
+	bad.Link = ""
+	good := validRelease()
+
+	valid, invalid := Filter("release", []models.Release{good, bad, good})
+	if len(valid) != 2 || len(invalid) != 1 {
+		t.Fatalf("Filter() = %d valid, %d invalid; want 2 and 1", len(valid), len(invalid))
+	}
+	if invalid[0].Kind != "release" || len(invalid[0].Errors) != 1 || !strings.Contains(invalid[0].Errors[0], "link") {
+		t.Errorf("invalid = %+v", invalid[0])
+	}
+}
@@ -0,0 +1,133 @@
+// Code generated by "firehose codegen ts" from firehose-go/internal/models; DO NOT EDIT.
+// Output schema version 1.1.0.
+
+export interface OutputData {
+  metadata: Metadata;
+  releases: Release[] | null;
+  news: Release[] | null;
+  feeds: FeedStatus[] | null;
+}
+
+export interface Metadata {
+  schemaVersion: string;
+  generatedAt: string;
+  generatedBy: string;
+  buildDuration: string;
+  stats: Stats;
+  performance: Performance;
+}
+
+export interface Release {
+  id: string;
+  title: string;
+  /** URL */
+  link: string;
+  /** RFC 3339 date-time */
+  pubDate: string;
+  content?: string;
+  contentSnippet?: string;
+  guid?: string;
+  projectName?: string;
+  projectDescription?: string;
+  projectStatus?: 'graduated' | 'incubating' | 'sandbox';
+  /** URL */
+  projectHomepage?: string;
+  /** URL */
+  feedUrl: string;
+  feedTitle?: string;
+  feedStatus: 'success' | 'error';
+  /** RFC 3339 date-time */
+  fetchedAt: string;
+  enclosures?: Enclosure[];
+}
+
+export interface FeedStatus {
+  /** URL */
+  feedUrl: string;
+  /** URL */
+  finalUrl?: string;
+  status: 'success' | 'error';
+  entriesCount?: number;
+  error?: string;
+  errorType?: 'network' | 'parse' | 'validation' | 'timeout';
+  fetchedAt: string;
+  duration: string;
+  warnings?: FeedWarning[];
+}
+
+export interface Stats {
+  feedsTotal: number;
+  feedsSuccessful: number;
+  feedsFailed: number;
+  feedsSkipped: number;
+  feedsWithWarnings: number;
+  invalidRecords: number;
+  releasesTotal: number;
+  newsTotal: number;
+  blogFeedsTotal: number;
+  landscapeProjectsTotal: number;
+  landscapeProjectsMatched: number;
+  warnings?: Record<string, number>;
+}
+
+export interface Performance {
+  landscapeFetchDuration: string;
+  feedsFetchDuration: string;
+  blogFetchDuration: string;
+  enrichmentDuration: string;
+  validationDuration: string;
+  outputDuration?: string;
+  requests: number;
+  bytesTransferred: number;
+  retries: number;
+  hosts?: HostPerformance[];
+}
+
+export interface Enclosure {
+  /** URL */
+  url: string;
+  type?: string;
+  length?: number;
+}
+
+export interface FeedWarning {
+  code: string;
+  count: number;
+  example?: string;
+}
+
+export interface HostPerformance {
+  host: string;
+  requests: number;
+  errors: number;
+  retries: number;
+  bytes: number;
+  p50: string;
+  p90: string;
+  p99: string;
+}
+
+export interface ShardIndex {
+  metadata: Metadata;
+  feeds: FeedStatus[] | null;
+  projects: ShardManifest[] | null;
+  months: ShardManifest[] | null;
+}
+
+export interface ShardManifest {
+  key: string;
+  path: string;
+  releases: number;
+  news: number;
+  sha256: string;
+  /** RFC 3339 date-time */
+  oldest: string;
+  /** RFC 3339 date-time */
+  newest: string;
+}
+
+export interface Shard {
+  key: string;
+  releases: Release[] | null;
+  news: Release[] | null;
+}
@@ -1,29 +1,32 @@
+import type { Release } from './releases';
+
 /**
- * FeedEntry — shape of a single release or blog post entry as produced
- * by the Go pipeline and written to src/data/releases.json.
+ * FeedEntry — shape of a single release or blog post entry as rendered by
+ * ReleaseCard.
  *
- * Fields are enriched by the CNCF Landscape integration in
- * firehose-go/internal/landscape/landscape.go.
+ * The fields come from Release, which is generated from the Go models
+ * (firehose-go/internal/models) by `firehose codegen ts`; regenerate
+ * src/lib/releases.d.ts instead of editing it. The core fields are required
+ * and the landscape and feed metadata stay optional, since pages build entries
+ * by hand. Pages add isoDate, a copy of pubDate, when they map
+ * src/data/releases.json into entries.
  */
-export interface FeedEntry {
-  id: string;
-  title: string;
-  link: string;
-  pubDate: string;
+export interface FeedEntry
+  extends Pick<Release, 'id' | 'title' | 'link' | 'pubDate'>,
+    Required<Pick<Release, 'content' | 'guid'>>,
+    Partial<
+      Pick<
+        Release,
+        | 'contentSnippet'
+        | 'projectName'
+        | 'projectDescription'
+        | 'projectStatus'
+        | 'projectHomepage'
+        | 'feedUrl'
+        | 'feedTitle'
+        | 'feedStatus'
+        | 'fetchedAt'
+      >
+    > {
   isoDate?: string;
-  content: string;
-  guid: string;
-
-  // Landscape-enriched fields (may be undefined for unmatched feeds)
-  projectName?: string;
-  projectDescription?: string;
-  projectStatus?: 'graduated' | 'incubating' | 'sandbox';
-  projectHomepage?: string;
-
-  // Feed metadata
-  feedUrl?: string;
-  feedTitle?: string;
-  feedStatus?: string;
-  contentSnippet?: string;
-  fetchedAt?: string;
 }
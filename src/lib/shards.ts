@@ -0,0 +1,57 @@
+/**
+ * Sharded output loader.
+ *
+ * With `output.shards: true` in firehose-go/config/feeds.yaml the Go pipeline
+ * writes public/releases/: index.json (metadata, feed statuses and a manifest
+ * per shard) plus projects/<slug>.json and months/<yyyy-mm>.json. Pages that
+ * only need recent items read the newest month shards at build time instead of
+ * the whole of src/data/releases.json, which is still read as a fallback when
+ * the shards have not been generated.
+ */
+
+import { existsSync, readFileSync } from 'node:fs';
+import { join } from 'node:path';
+import type { Metadata, OutputData, Release, Shard, ShardIndex } from './releases';
+
+const SHARD_DIR = join(process.cwd(), 'public', 'releases');
+const OUTPUT_PATH = join(process.cwd(), 'src', 'data', 'releases.json');
+
+type Kind = 'releases' | 'news';
+
+function readJSON<T>(path: string): T {
+  return JSON.parse(readFileSync(path, 'utf-8')) as T;
+}
+
+export interface Recent {
+  metadata: Metadata;
+  items: Release[];
+}
+
+/**
+ * Returns the output metadata and the newest `limit` items of `kind`, newest
+ * first. Month shards are read newest first until enough items are collected.
+ */
+export function loadRecent(kind: Kind, limit: number): Recent {
+  const indexPath = join(SHARD_DIR, 'index.json');
+  let metadata: Metadata;
+  let items: Release[] = [];
+  if (existsSync(indexPath)) {
+    const index = readJSON<ShardIndex>(indexPath);
+    metadata = index.metadata;
+    // index.months is sorted newest first by the pipeline.
+    for (const month of index.months ?? []) {
+      if (items.length >= limit) break;
+      if (month[kind] === 0) continue;
+      const shard = readJSON<Shard>(join(SHARD_DIR, month.path));
+      items = items.concat(shard[kind] ?? []);
+    }
+  } else {
+    const output = readJSON<OutputData>(OUTPUT_PATH);
+    metadata = output.metadata;
+    items = output[kind] ?? [];
+  }
+  items = items
+    .sort((a, b) => new Date(b.pubDate).getTime() - new Date(a.pubDate).getTime())
+    .slice(0, limit);
+  return { metadata, items };
+}
@@ -1,10 +1,13 @@
 import type { APIRoute } from 'astro';
 import { marked } from 'marked';
-import releasesData from '../data/releases.json';
+import { loadRecent } from '../lib/shards';
 
 export const GET: APIRoute = async ({ site }) => {
+  // Only the 100 newest items are used, so read them from the newest month shards
+  const { metadata, items } = loadRecent('releases', 100);
+
   // Transform JSON releases to match expected format
-  const releases = ((releasesData as any).releases as any[]).map((r: any) => ({
+  const releases = items.map((r: any) => ({
     data: {
       title: r.title,
       link: r.link,
@@ -41,7 +44,7 @@ export const GET: APIRoute = async ({ site }) => {
 <rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom">
   <channel>
     <title>CNCF Project Releases</title>
-    <description>Aggregated release feed from ${releases.length} CNCF projects. Stay updated with the latest releases from the cloud native ecosystem.</description>
+    <description>Aggregated release feed from ${metadata.stats.releasesTotal} CNCF projects. Stay updated with the latest releases from the cloud native ecosystem.</description>
     <link>${siteUrl}</link>
     <atom:link href="${feedUrl}" rel="self" type="application/rss+xml" />
     <language>en-us</language>
@@ -1,10 +1,13 @@
 import type { APIRoute } from 'astro';
 import { marked } from 'marked';
-import releasesData from '../data/releases.json';
+import { loadRecent } from '../lib/shards';
 
 export const GET: APIRoute = async ({ site }) => {
+  // Only the 100 newest items are used, so read them from the newest month shards
+  const { items } = loadRecent('news', 100);
+
   // Transform JSON news items to match expected format
-  const newsItems = ((releasesData as any).news as any[]).map((r: any) => ({
+  const newsItems = items.map((r: any) => ({
     data: {
       title: r.title,
       link: r.link,